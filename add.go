@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -16,12 +18,19 @@ var addCmd = &cobra.Command{
 Examples:
   brain add "Redis caching reduced API latency by 60%"
   brain add "Use context.WithTimeout for API calls" --tags go,best-practices
-  brain add "Team prefers tabs over spaces" --project myapp`,
+  brain add "Team prefers tabs over spaces" --project myapp
+  brain add "Pool sizes should match the cores" --auto-tag
+
+Without --tags, tags are suggested from the most similar notes (and the
+chat model with --llm-tags). You're asked whether to use them, or with
+--auto-tag they're added straight away.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		content := args[0]
 		tags, _ := cmd.Flags().GetStringSlice("tags")
 		project, _ := cmd.Flags().GetString("project")
+		autoTag, _ := cmd.Flags().GetBool("auto-tag")
+		llmTags, _ := cmd.Flags().GetBool("llm-tags")
 
 		b, err := brain.New()
 		if err != nil {
@@ -35,11 +44,25 @@ Examples:
 			Timestamp: time.Now(),
 		}
 
-		if err := b.AddNote(note); err != nil {
+		if err := b.AddNote(note); errors.Is(err, brain.ErrEmbeddingPending) {
+			fmt.Printf("⚠ %v\n", explainError(err))
+			fmt.Printf("  The note won't show up in searches until it's embedded (ID: %s)\n", note.ID)
+			fmt.Println("  It will be retried on the next run, or now with: brain embed --pending")
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("failed to add note: %w", err)
 		}
 
 		fmt.Printf("✓ Note added successfully (ID: %s)\n", note.ID)
+
+		if len(tags) == 0 {
+			t := newTagger(b, brain.TagOptions{UseLLM: llmTags}, autoTag)
+			if suggested := t.suggest(cmd.Context(), note); len(suggested) > 0 {
+				if _, err := t.apply(note, suggested); err != nil && err != io.EOF {
+					return err
+				}
+			}
+		}
 		return nil
 	},
 }
@@ -48,4 +71,6 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.Flags().StringSliceP("tags", "t", []string{}, "Tags for the note")
 	addCmd.Flags().StringP("project", "p", "", "Project this note belongs to")
+	addCmd.Flags().Bool("auto-tag", false, "Add the suggested tags without asking when --tags isn't given")
+	addCmd.Flags().Bool("llm-tags", false, "Ask the chat model for tag suggestions as well")
 }
@@ -0,0 +1,258 @@
+package brain
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NoAnswer is the reply when no note is relevant enough to answer from
+const NoAnswer = "I don't have any notes that might answer that question."
+
+// citationPattern matches citations like [2], [1, 3] or [3f2a9c1e] in an
+// answer, and citationRefPattern each reference inside one
+var (
+	citationPattern    = regexp.MustCompile(`\[\s*(?:id\s+)?[0-9a-fA-F-]+(?:\s*,\s*(?:id\s+)?[0-9a-fA-F-]+)*\s*\]`)
+	citationRefPattern = regexp.MustCompile(`[0-9a-fA-F-]+`)
+)
+
+// minCitedIDLength is the shortest note ID prefix accepted as a citation,
+// as for @id mentions. Shorter numbers are source numbers.
+const minCitedIDLength = 6
+
+// AnswerOptions controls Answer and Chat
+type AnswerOptions struct {
+	// Grounded asks the model to cite a note ID for every statement, and
+	// only gives it notes scoring at least llm.grounded_min_score
+	Grounded bool
+	OnToken  func(string) // receives the answer as it's generated, may be nil
+
+	// Context is where the question is asked, for the project and context
+	// prompt variables. It may be nil.
+	Context *Context
+}
+
+// Answer is an LLM's answer to a question about the notes
+type Answer struct {
+	Text    string
+	Sources []SearchResult // the notes the model was given; [n] in Text cites Sources[n-1]
+	Model   string
+	Usage   TokenUsage
+}
+
+// Cited returns the sources the answer cites, by number or note ID, in
+// the order they were given to the model
+func (a *Answer) Cited() []SearchResult {
+	cited := make(map[int]bool)
+	for _, ref := range citationRefs(a.Text) {
+		if i, ok := a.source(ref); ok {
+			cited[i] = true
+		}
+	}
+
+	var results []SearchResult
+	for i, r := range a.Sources {
+		if cited[i] {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// WithMentions returns the answer text with each citation replaced by
+// @id mentions of the notes it cites, so a note saved from the answer
+// links to its sources
+func (a *Answer) WithMentions() string {
+	return citationPattern.ReplaceAllStringFunc(a.Text, func(citation string) string {
+		var mentions []string
+		for _, ref := range citationRefs(citation) {
+			if i, ok := a.source(ref); ok {
+				mentions = append(mentions, "@"+ShortID(a.Sources[i].Note.ID))
+			}
+		}
+		if len(mentions) == 0 {
+			return citation
+		}
+		return "(" + strings.Join(mentions, " ") + ")"
+	})
+}
+
+// GroundingCheck lists the parts of an answer its sources don't back up
+type GroundingCheck struct {
+	Unknown []string // citations of notes the model wasn't given
+	Uncited []string // statements that cite no note
+}
+
+// OK reports whether every statement cites a note the model was given
+func (c GroundingCheck) OK() bool {
+	return len(c.Unknown) == 0 && len(c.Uncited) == 0
+}
+
+// Check verifies that each statement in the answer cites a note and that
+// every cited note is one of its sources
+func (a *Answer) Check() GroundingCheck {
+	var check GroundingCheck
+	for _, ref := range citationRefs(a.Text) {
+		if _, ok := a.source(ref); !ok && !containsString(check.Unknown, ref) {
+			check.Unknown = append(check.Unknown, ref)
+		}
+	}
+
+	for _, sentence := range statements(a.Text) {
+		if sentence == NoAnswer || strings.HasSuffix(sentence, ":") {
+			continue
+		}
+		if !citationPattern.MatchString(sentence) {
+			check.Uncited = append(check.Uncited, sentence)
+		}
+	}
+	return check
+}
+
+// source returns the index of the source a citation reference points to,
+// either by number from 1 or by note ID prefix
+func (a *Answer) source(ref string) (int, bool) {
+	if len(ref) < minCitedIDLength {
+		n, err := strconv.Atoi(ref)
+		if err != nil || n < 1 || n > len(a.Sources) {
+			return 0, false
+		}
+		return n - 1, true
+	}
+
+	ref = strings.ToLower(ref)
+	for i, r := range a.Sources {
+		if strings.HasPrefix(strings.ToLower(r.Note.ID), ref) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// citationRefs returns the references inside every citation in text,
+// skipping ones too short to be an ID that aren't numbers either
+func citationRefs(text string) []string {
+	var refs []string
+	for _, citation := range citationPattern.FindAllString(text, -1) {
+		for _, ref := range citationRefPattern.FindAllString(citation, -1) {
+			if _, err := strconv.Atoi(ref); err == nil || len(ref) >= minCitedIDLength {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// statements splits an answer into sentences and list items. Citations
+// placed after a sentence's full stop stay with that sentence.
+func statements(text string) []string {
+	var result []string
+	for _, sentence := range splitSentences(text) {
+		if !strings.ContainsFunc(citationPattern.ReplaceAllString(sentence, ""), unicode.IsLetter) {
+			// Nothing but citations or punctuation
+			if len(result) > 0 && citationPattern.MatchString(sentence) {
+				result[len(result)-1] += " " + sentence
+			}
+			continue
+		}
+		result = append(result, strings.TrimLeft(sentence, "-*• "))
+	}
+	return result
+}
+
+// HasLLM reports whether a chat model is configured
+func (b *Brain) HasLLM() bool {
+	return b.llm != nil
+}
+
+// Answer asks the LLM to answer the question from the search results.
+// Results are packed into the prompt best first until the configured
+// token budget runs out. It returns ErrNoLLM when no model is configured,
+// and ErrNoRelevantNotes when there are no results to answer from, which
+// in grounded mode includes none scoring high enough.
+func (b *Brain) Answer(ctx context.Context, question string, results []SearchResult, opts AnswerOptions) (*Answer, error) {
+	if b.llm == nil {
+		return nil, ErrNoLLM
+	}
+
+	results = b.groundingResults(results, opts)
+	if len(results) == 0 {
+		return nil, ErrNoRelevantNotes
+	}
+
+	notes, sources := packNotes(results, b.config.LLM.ContextTokens)
+	messages, err := b.prompts.render("ask", opts.promptData(question, notes))
+	if err != nil {
+		return nil, err
+	}
+	completion, err := b.complete(ctx, messages, opts.OnToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Answer{
+		Text:    completion.Content,
+		Sources: sources,
+		Model:   completion.Model,
+		Usage:   completion.Usage,
+	}, nil
+}
+
+// promptData fills in the prompt variables for a question
+func (o AnswerOptions) promptData(question, notes string) promptData {
+	data := promptData{
+		Question: question,
+		Notes:    notes,
+		Grounded: o.Grounded,
+		Context:  describeContext(o.Context),
+	}
+	if o.Context != nil {
+		data.Project = o.Context.Project
+	}
+	return data
+}
+
+// groundingResults drops results below llm.grounded_min_score in
+// grounded mode, so the model isn't asked to answer from weak matches
+func (b *Brain) groundingResults(results []SearchResult, opts AnswerOptions) []SearchResult {
+	if !opts.Grounded {
+		return results
+	}
+	return dropBelow(append([]SearchResult(nil), results...), b.config.LLM.GroundedMinScore)
+}
+
+// complete runs a completion, streaming it to onToken when it isn't nil.
+// Models that can't stream pass onToken the whole reply at the end. The
+// call is checked against the budget and logged.
+func (b *Brain) complete(ctx context.Context, messages []Message, onToken func(string)) (*Completion, error) {
+	ctx = withUsageLog(ctx, b.usage)
+	if onToken == nil {
+		return b.llm.Complete(ctx, messages)
+	}
+	if streaming, ok := b.llm.(StreamingLLM); ok {
+		return streaming.Stream(ctx, messages, onToken)
+	}
+	return completeWhole(ctx, b.llm, messages, onToken)
+}
+
+// ShortID returns the first characters of a note ID, enough to tell notes
+// apart and accepted wherever an ID is
+func ShortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// FirstLine returns the first non-empty line of text, e.g. to show a note
+// or chat by its opening line
+func FirstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnswerCited(t *testing.T) {
+	sources := []SearchResult{
+		{Note: &Note{ID: "3f2a9c1e-aaaa-bbbb"}},
+		{Note: &Note{ID: "9b07d4aa-cccc-dddd"}},
+		{Note: &Note{ID: "c0ffee00-eeee-ffff"}},
+	}
+
+	tests := []struct {
+		text     string
+		expected []int // indexes into sources
+	}{
+		{"Use pooling [1].", []int{0}},
+		{"Cache it [3][1], and again [3].", []int{0, 2}},
+		{"Both apply [1, 2].", []int{0, 1}},
+		{"By ID [9b07d4aa] and [id C0FFEE00].", []int{1, 2}},
+		{"Out of range [7] or unknown [deadbeef].", nil},
+		{"No citations here.", nil},
+		{"Arrays like a[i] and a[b] aren't citations.", nil},
+	}
+
+	for _, tt := range tests {
+		answer := &Answer{Text: tt.text, Sources: sources}
+		var got []int
+		for _, r := range answer.Cited() {
+			for i := range sources {
+				if sources[i].Note == r.Note {
+					got = append(got, i)
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Cited() for %q = %v, want %v", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestAnswerCheck(t *testing.T) {
+	sources := []SearchResult{
+		{Note: &Note{ID: "3f2a9c1e-aaaa-bbbb"}},
+		{Note: &Note{ID: "9b07d4aa-cccc-dddd"}},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		unknown []string
+		uncited []string
+	}{
+		{
+			name: "every statement cited",
+			text: "Pool connections [3f2a9c1e]. Cache reads.[9b07d4aa]\n\nIn short:\n- Size pools to cores [3f2a9c1e, 9b07d4aa]",
+		},
+		{
+			name: "no answer",
+			text: NoAnswer,
+		},
+		{
+			name:    "cites a note it wasn't given",
+			text:    "Pool connections [3f2a9c1e]. Use Redis [deadbeef].",
+			unknown: []string{"deadbeef"},
+		},
+		{
+			name:    "statement without a citation",
+			text:    "Pool connections [3f2a9c1e]. Also shard the database. Version 1.5 helps [9b07d4aa].",
+			uncited: []string{"Also shard the database."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := (&Answer{Text: tt.text, Sources: sources}).Check()
+			if !reflect.DeepEqual(check.Unknown, tt.unknown) || !reflect.DeepEqual(check.Uncited, tt.uncited) {
+				t.Errorf("Check() = %+v, want unknown %v and uncited %v", check, tt.unknown, tt.uncited)
+			}
+			if check.OK() != (tt.unknown == nil && tt.uncited == nil) {
+				t.Errorf("OK() = %v for %+v", check.OK(), check)
+			}
+		})
+	}
+}
+
+func TestBrainAnswer(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, content := range []string{
+		"Postgres connection pools should be sized to the number of cores",
+		"Redis caching for hot queries",
+	} {
+		if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+	results, err := b.SearchWithOptions("postgres connection pools", SearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if _, err := b.Answer(context.Background(), "How big?", results, AnswerOptions{}); !errors.Is(err, ErrNoLLM) {
+		t.Fatalf("Expected ErrNoLLM without a model, got %v", err)
+	}
+
+	server, last := newChatStub(t, "Size the pool to the core count [1].")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	answer, err := b.Answer(context.Background(), "How big should the pool be?", results, AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+
+	if len(answer.Sources) != len(results) {
+		t.Errorf("Expected all %d results as sources, got %d", len(results), len(answer.Sources))
+	}
+	cited := answer.Cited()
+	if len(cited) != 1 || cited[0].Note != results[0].Note {
+		t.Errorf("Expected the answer to cite the first result, got %v", cited)
+	}
+
+	prompt := last.Messages[len(last.Messages)-1].Content
+	if !strings.Contains(prompt, "[1] id "+ShortID(results[0].Note.ID)) || !strings.Contains(prompt, "How big should the pool be?") {
+		t.Errorf("Expected the numbered notes and question in the prompt:\n%s", prompt)
+	}
+}
+
+func TestAnswerWithMentions(t *testing.T) {
+	answer := &Answer{
+		Text: "Pool connections [1] and cache reads [1, 2]. Not a source [7].",
+		Sources: []SearchResult{
+			{Note: &Note{ID: "3f2a9c1e-aaaa-bbbb"}},
+			{Note: &Note{ID: "9b07d4aa-cccc-dddd"}},
+		},
+	}
+
+	want := "Pool connections (@3f2a9c1e) and cache reads (@3f2a9c1e @9b07d4aa). Not a source [7]."
+	if got := answer.WithMentions(); got != want {
+		t.Errorf("WithMentions() = %q, want %q", got, want)
+	}
+}
+
+func TestBrainAnswerGrounded(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "Size the pool to the core count [aaaaaaaa].")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	strong := SearchResult{Note: &Note{ID: "aaaaaaaa-1111", Content: "Pool size matches the cores"}, Similarity: 0.8}
+	weak := SearchResult{Note: &Note{ID: "bbbbbbbb-2222", Content: "Redis notes"}, Similarity: 0.2}
+
+	answer, err := b.Answer(context.Background(), "How big?", []SearchResult{strong, weak}, AnswerOptions{Grounded: true})
+	if err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+	if len(answer.Sources) != 1 || answer.Sources[0].Note != strong.Note {
+		t.Errorf("Expected only the note above grounded_min_score as a source, got %d", len(answer.Sources))
+	}
+	if !answer.Check().OK() {
+		t.Errorf("Expected the answer to pass the grounding check, got %+v", answer.Check())
+	}
+	if last.Messages[0].Content != groundedSystemPrompt {
+		t.Errorf("Expected the grounded system prompt, got %q", last.Messages[0].Content)
+	}
+
+	_, err = b.Answer(context.Background(), "How big?", []SearchResult{weak}, AnswerOptions{Grounded: true})
+	if !errors.Is(err, ErrNoRelevantNotes) {
+		t.Errorf("Expected ErrNoRelevantNotes when every note scores below the threshold, got %v", err)
+	}
+	if _, err := b.Answer(context.Background(), "How big?", []SearchResult{weak}, AnswerOptions{}); err != nil {
+		t.Errorf("Expected weak notes to be used outside grounded mode, got %v", err)
+	}
+}
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/brain-cli/internal/brain"
@@ -12,13 +17,34 @@ var askCmd = &cobra.Command{
 	Short: "Ask a question about your notes",
 	Long: `Ask a natural language question and get an answer based on your notes.
 
+The most relevant notes are sent to a chat model (see llm: in the config),
+which answers with numbered citations like [1] pointing back to them.
+Without a model, or with --no-llm, the relevant notes are shown instead.
+The answer is printed as it's generated; Ctrl-C stops it.
+
+With --grounded (or llm.grounded in the config) every statement must cite
+the ID of a note, the citations are checked against the notes the model
+was given, and only notes scoring at least llm.grounded_min_score are
+used. If none do, there is no answer rather than a guess.
+
 Examples:
   brain ask "What have I learned about database optimization?"
   brain ask "How should I handle errors in Go?"
-  brain ask "What are the team's coding standards?"`,
+  brain ask "What are the team's coding standards?"
+  brain ask "How do we deploy?" --min-score 0.3 --explain
+  brain ask "Why did we pick Postgres?" --limit 10
+  brain ask "How do we deploy?" --no-llm
+  brain ask "What's our on-call policy?" --grounded`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		question := args[0]
+		explain, _ := cmd.Flags().GetBool("explain")
+		limit, _ := cmd.Flags().GetInt("limit")
+		noLLM, _ := cmd.Flags().GetBool("no-llm")
+		grounded, _ := cmd.Flags().GetBool("grounded")
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
 
 		b, err := brain.New()
 		if err != nil {
@@ -26,36 +52,156 @@ Examples:
 		}
 
 		// Search for relevant notes
-		results, err := b.Search(question, 5, nil)
+		// The question is plain text, not query syntax
+		results, err := b.SearchWithOptions(question, brain.SearchOptions{
+			Limit:     limit,
+			MinScore:  minScore(cmd, b),
+			PlainText: true,
+		})
 		if err != nil {
 			return fmt.Errorf("search failed: %w", err)
 		}
 
 		if len(results) == 0 {
-			fmt.Println("I don't have any notes that might answer that question.")
-			fmt.Println("Try adding some notes first with: brain add \"your insight\"")
+			printNoAnswer()
 			return nil
 		}
 
+		if !noLLM {
+			// Ctrl-C abandons the answer rather than killing the process
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			out := &tokenPrinter{}
+			grounded = grounded || b.Config().LLM.Grounded
+			workingContext := detectContext()
+			answer, err := b.Answer(ctx, question, results, brain.AnswerOptions{
+				Grounded: grounded,
+				OnToken:  out.print,
+				Context:  &workingContext,
+			})
+			out.finish()
+			switch {
+			case err == nil:
+				printSources(answer, explain)
+				if grounded {
+					printGroundingCheck(answer)
+				}
+				recordShown(b, answer.Sources)
+				return nil
+			case errors.Is(err, brain.ErrNoRelevantNotes):
+				printNoAnswer()
+				return nil
+			case errors.Is(err, context.Canceled):
+				fmt.Println("⚠ Cancelled")
+				return nil
+			case !errors.Is(err, brain.ErrNoLLM):
+				return fmt.Errorf("failed to generate an answer: %w", err)
+			}
+		}
+
 		fmt.Printf("💡 Based on your notes, here's what I found:\n\n")
 		fmt.Printf("Question: %s\n\n", question)
-		
+
 		fmt.Println("Relevant notes:")
 		for i, result := range results {
 			fmt.Printf("%d. %s\n", i+1, result.Note.Content)
+			if result.Passage != "" {
+				fmt.Printf("   Matched passage:\n%s\n", indentPassage(result.Passage))
+			}
 			if len(result.Note.Tags) > 0 {
 				fmt.Printf("   Tags: %v\n", result.Note.Tags)
 			}
-			fmt.Printf("   Relevance: %.0f%%\n\n", result.Similarity*100)
+			fmt.Printf("   Relevance: %.0f%%\n", result.Similarity*100)
+			if explain {
+				printScoreBreakdown(result)
+			}
+			fmt.Println()
 		}
+		recordShown(b, results)
 
-		// TODO: In the future, we could use an LLM to synthesize these notes
-		// into a natural language answer. For now, just show the relevant notes.
+		if !noLLM {
+			fmt.Println("💡 Set OPENAI_API_KEY, or configure llm: in ~/.brain/config.yaml, for a written answer")
+		}
 
 		return nil
 	},
 }
 
+// printNoAnswer says there's nothing to answer from
+func printNoAnswer() {
+	fmt.Println(brain.NoAnswer)
+	fmt.Println("Try adding some notes first with: brain add \"your insight\"")
+}
+
+// printGroundingCheck warns about statements in a grounded answer that
+// its notes don't back up
+func printGroundingCheck(answer *brain.Answer) {
+	check := answer.Check()
+	if check.OK() {
+		fmt.Println("✓ Every statement cites a note it was given")
+		return
+	}
+	if len(check.Unknown) > 0 {
+		fmt.Printf("⚠ Cites notes it wasn't given: %s\n", strings.Join(check.Unknown, ", "))
+	}
+	if len(check.Uncited) > 0 {
+		fmt.Println("⚠ Not backed by a cited note:")
+		for _, statement := range check.Uncited {
+			fmt.Printf("  - %s\n", statement)
+		}
+	}
+}
+
+// tokenPrinter prints an answer as it's streamed
+type tokenPrinter struct {
+	started bool
+}
+
+func (p *tokenPrinter) print(token string) {
+	if !p.started {
+		fmt.Print("💡 ")
+		p.started = true
+	}
+	fmt.Print(token)
+}
+
+// finish ends the answer, complete or not, with a blank line
+func (p *tokenPrinter) finish() {
+	if p.started {
+		fmt.Print("\n\n")
+	}
+}
+
+// printSources lists the notes an answer cites by their citation number.
+// If it cites none, every note it was given is listed.
+func printSources(answer *brain.Answer, explain bool) {
+	cited := make(map[*brain.Note]bool)
+	for _, r := range answer.Cited() {
+		cited[r.Note] = true
+	}
+
+	if len(cited) > 0 {
+		fmt.Println("Sources:")
+	} else {
+		fmt.Println("Notes used:")
+	}
+	for i, r := range answer.Sources {
+		if len(cited) > 0 && !cited[r.Note] {
+			continue
+		}
+		fmt.Printf("  [%d] %s  [%s] %s\n", i+1, brain.ShortID(r.Note.ID), r.Note.Timestamp.Format("2006-01-02"), strings.TrimRight(fit(brain.FirstLine(r.Note.Content), 60), " "))
+		if explain {
+			printScoreBreakdown(r)
+		}
+	}
+	fmt.Println()
+}
+
 func init() {
 	rootCmd.AddCommand(askCmd)
+	askCmd.Flags().IntP("limit", "l", 5, "Maximum number of notes to base the answer on")
+	askCmd.Flags().Bool("no-llm", false, "Just show the relevant notes, without a written answer")
+	askCmd.Flags().Bool("grounded", false, "Require a cited note for every statement and check the citations")
+	addScoreFlags(askCmd)
 }
@@ -0,0 +1,206 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// autoTagNeighbours is how many similar notes vote on a note's tags
+	autoTagNeighbours = 8
+
+	// autoTagMinSimilarity is how similar a note must be to vote, unless
+	// search.min_score is higher. Weakly related notes share words, not
+	// topics.
+	autoTagMinSimilarity = 0.3
+
+	// autoTagMinShare is the share of the neighbours' similarity a tag
+	// needs to be suggested
+	autoTagMinShare = 0.3
+
+	// maxSuggestedTags caps the suggestions for one note
+	maxSuggestedTags = 5
+
+	// autoTagVocabulary is how many existing tags are shown to the LLM
+	autoTagVocabulary = 100
+
+	// autoTagNoteTokens is how much of the note is sent to the LLM
+	autoTagNoteTokens = 1000
+)
+
+const autoTagPrompt = `You tag notes in a personal knowledge base.
+Reply with one to three short, lowercase tags for the note, separated by commas, and nothing else.
+Use tags from the existing list whenever they fit. Use hyphens instead of spaces.`
+
+// TagOptions controls how tags are suggested
+type TagOptions struct {
+	// UseLLM also asks the chat model, if one is configured
+	UseLLM bool
+}
+
+// SuggestTags suggests tags for a note from the tags of the most similar
+// notes, weighted by similarity, so it works offline with the stored
+// embeddings. With UseLLM the chat model is asked as well and its tags
+// come first. Tags the note already has aren't suggested.
+func (b *Brain) SuggestTags(ctx context.Context, note *Note, opts TagOptions) ([]string, error) {
+	suggestions := b.neighbourTags(note)
+
+	if opts.UseLLM && b.llm != nil {
+		llmTags, err := b.classify(ctx, note)
+		if err != nil {
+			return suggestions, fmt.Errorf("failed to ask the LLM for tags: %w", err)
+		}
+		suggestions = mergeTags(llmTags, suggestions)
+	}
+
+	var tags []string
+	for _, tag := range suggestions {
+		if !containsString(note.Tags, tag) && len(tags) < maxSuggestedTags {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// neighbourTags scores the tags of the notes closest to note by the share
+// of their total similarity, best first
+func (b *Brain) neighbourTags(note *Note) []string {
+	embedding := b.storedEmbedding(note)
+	if len(embedding) == 0 {
+		return nil // not embedded yet
+	}
+
+	results, err := b.vectorStore.Search(embedding, autoTagNeighbours+1, nil)
+	if err != nil {
+		return nil
+	}
+
+	minSimilarity := math.Max(autoTagMinSimilarity, b.config.Search.MinScore)
+	scores := make(map[string]float64)
+	var total float64
+	for _, r := range results {
+		if r.Note.ID == note.ID || r.Similarity < minSimilarity {
+			continue
+		}
+		// Untagged neighbours count too, so a lone tagged note among
+		// untagged ones doesn't decide
+		total += r.Similarity
+		for _, tag := range r.Note.Tags {
+			scores[tag] += r.Similarity
+		}
+	}
+
+	var tags []string
+	for tag, score := range scores {
+		if score/total >= autoTagMinShare {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if scores[tags[i]] != scores[tags[j]] {
+			return scores[tags[i]] > scores[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// classify asks the chat model for tags, showing it the tags already in
+// use so it reuses them
+func (b *Brain) classify(ctx context.Context, note *Note) ([]string, error) {
+	var prompt strings.Builder
+	if vocabulary := b.tagVocabulary(); len(vocabulary) > 0 {
+		fmt.Fprintf(&prompt, "Existing tags: %s\n\n", strings.Join(vocabulary, ", "))
+	}
+	prompt.WriteString("Note:\n")
+	prompt.WriteString(truncateRunes(note.Content, autoTagNoteTokens*charsPerToken))
+
+	completion, err := b.complete(ctx, []Message{
+		{Role: "system", Content: autoTagPrompt},
+		{Role: "user", Content: prompt.String()},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseTags(completion.Content), nil
+}
+
+// tagVocabulary returns the tags in use, most used first
+func (b *Brain) tagVocabulary() []string {
+	counts := make(map[string]int)
+	for _, note := range b.vectorStore.GetAllNotes() {
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > autoTagVocabulary {
+		tags = tags[:autoTagVocabulary]
+	}
+	return tags
+}
+
+// parseTags reads a comma or newline separated list of tags from an LLM
+// reply, tolerating "#tag", bullets and a "Tags:" prefix
+func parseTags(reply string) []string {
+	reply = strings.TrimSpace(reply)
+	if prefix, rest, ok := strings.Cut(reply, ":"); ok && strings.EqualFold(strings.TrimSpace(prefix), "tags") {
+		reply = rest
+	}
+
+	var tags []string
+	for _, field := range strings.FieldsFunc(reply, func(r rune) bool { return r == ',' || r == '\n' }) {
+		tag := strings.ToLower(strings.Trim(strings.TrimSpace(field), "-*#.\"'` "))
+		tag = strings.Join(strings.Fields(tag), "-")
+		if tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// mergeTags appends the tags of later lists that aren't already present
+func mergeTags(lists ...[]string) []string {
+	var merged []string
+	for _, list := range lists {
+		for _, tag := range list {
+			if !containsString(merged, tag) {
+				merged = append(merged, tag)
+			}
+		}
+	}
+	return merged
+}
+
+// UntaggedNotes returns the notes without any tags, oldest first
+func (b *Brain) UntaggedNotes() []*Note {
+	var notes []*Note
+	for _, note := range b.vectorStore.GetAllNotes() {
+		if len(note.Tags) == 0 {
+			notes = append(notes, note)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].Timestamp.Before(notes[j].Timestamp)
+	})
+	return notes
+}
+
+// AddTags adds tags to a note and saves it
+func (b *Brain) AddTags(note *Note, tags ...string) error {
+	note.Tags = mergeTags(note.Tags, tags)
+	return b.saveNotes()
+}
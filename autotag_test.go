@@ -0,0 +1,103 @@
+package brain
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSuggestTags(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+
+	notes := []*Note{
+		{Content: "Postgres connection pool size should match the cores", Tags: []string{"postgres", "database"}},
+		{Content: "Postgres vacuum settings for busy connection tables", Tags: []string{"postgres"}},
+		{Content: "Postgres connection limits and pgbouncer pooling", Tags: []string{"postgres", "database"}},
+		{Content: "Kubernetes liveness probes restart stuck pods", Tags: []string{"kubernetes"}},
+	}
+	for _, note := range notes {
+		note.Timestamp = time.Now()
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	note := &Note{Content: "Postgres connection pool exhausted under load", Timestamp: time.Now()}
+	if err := b.AddNote(note); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+
+	tags, err := b.SuggestTags(context.Background(), note, TagOptions{})
+	if err != nil {
+		t.Fatalf("SuggestTags failed: %v", err)
+	}
+	if len(tags) == 0 || tags[0] != "postgres" {
+		t.Fatalf("Expected postgres first, got %v", tags)
+	}
+	if containsString(tags, "kubernetes") {
+		t.Errorf("Expected no tags from unrelated notes, got %v", tags)
+	}
+
+	// Tags the note already has aren't suggested again
+	if err := b.AddTags(note, "postgres"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+	if tags, _ := b.SuggestTags(context.Background(), note, TagOptions{}); containsString(tags, "postgres") {
+		t.Errorf("Expected existing tags to be skipped, got %v", tags)
+	}
+
+	if untagged := b.UntaggedNotes(); len(untagged) != 0 {
+		t.Errorf("Expected no untagged notes, got %d", len(untagged))
+	}
+}
+
+func TestSuggestTagsLLM(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "Tags: #Performance, connection pooling")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	tagged := &Note{Content: "Postgres connection pool sizing", Tags: []string{"postgres"}, Timestamp: time.Now()}
+	note := &Note{Content: "Postgres connection pool exhausted under load", Timestamp: time.Now()}
+	for _, n := range []*Note{tagged, note} {
+		if err := b.AddNote(n); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	// Without UseLLM only the neighbours are used
+	tags, err := b.SuggestTags(context.Background(), note, TagOptions{})
+	if err != nil || !reflect.DeepEqual(tags, []string{"postgres"}) {
+		t.Fatalf("Expected [postgres] from the neighbours, got %v (%v)", tags, err)
+	}
+
+	tags, err = b.SuggestTags(context.Background(), note, TagOptions{UseLLM: true})
+	if err != nil {
+		t.Fatalf("SuggestTags failed: %v", err)
+	}
+	if want := []string{"performance", "connection-pooling", "postgres"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Expected %v, got %v", want, tags)
+	}
+	if len(last.Messages) != 2 || last.Messages[1].Content != "Existing tags: postgres\n\nNote:\n"+note.Content {
+		t.Errorf("Unexpected request %+v", last.Messages)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  []string
+	}{
+		{"go, http", []string{"go", "http"}},
+		{"Tags: #Go, #HTTP.", []string{"go", "http"}},
+		{"- connection pooling\n- postgres\n- Postgres", []string{"connection-pooling", "postgres"}},
+		{"ops/k8s", []string{"ops/k8s"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseTags(tt.reply); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTags(%q) = %v, want %v", tt.reply, got, tt.want)
+		}
+	}
+}
@@ -2,43 +2,94 @@ package brain
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrNoteNotFound is returned when no note has the requested ID
+var ErrNoteNotFound = errors.New("note not found")
+
 type Note struct {
 	ID        string    `json:"id"`
 	Content   string    `json:"content"`
 	Tags      []string  `json:"tags"`
 	Project   string    `json:"project,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
-	Embedding []float32 `json:"-"` // Don't serialize, computed on demand
+	Embedding []float32 `json:"-"`                 // Saved in embeddings.gob rather than here
+	Chunks    []Chunk   `json:"-"`                 // Passages of long notes, also in embeddings.gob
+	Pending   bool      `json:"pending,omitempty"` // Saved but not yet embedded
+
+	AccessCount int  `json:"access_count,omitempty"` // Times shown in search, ask or context
+	Pinned      bool `json:"pinned,omitempty"`       // Always ranked a little higher
+
+	Review *ReviewState `json:"review,omitempty"` // Spaced repetition schedule, nil until first reviewed
 }
 
 type SearchResult struct {
 	Note       *Note
 	Similarity float64
+	Passage    string         // Best matching passage of a long note, empty otherwise
+	Score      ScoreBreakdown // How Similarity was computed
+}
+
+type Context struct {
+	Directory   string
+	Project     string
+	Description string
+	Keywords    []string
 }
 
 type Brain struct {
 	dataDir    string
 	notesPath  string
+	config     *Config
 	embedder   Embedder
 	vectorStore VectorStore
+	keywords   *keywordIndex
+	synonyms   Synonyms
+	llm        LLM // nil when no chat model is configured
+	prompts    *promptSet
+	usage      *usageLog
+
+	// Set when a note is embedded, so the embeddings are saved with the notes
+	embeddingsChanged bool
+
+	// The last query embedding, so paging through results doesn't embed
+	// the same query again
+	lastQuery          string
+	lastQueryEmbedding []float32
+}
+
+// defaultDataDir returns ~/.brain, where everything is kept
+func defaultDataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".brain"), nil
+}
+
+// configPath is the --config file, or config.yaml in the data directory
+func configPath(dataDir string) string {
+	if ConfigPath != "" {
+		return ConfigPath
+	}
+	return filepath.Join(dataDir, "config.yaml")
 }
 
 // New creates a new Brain instance
 func New() (*Brain, error) {
-	homeDir, err := os.UserHomeDir()
+	dataDir, err := defaultDataDir()
 	if err != nil {
 		return nil, err
 	}
-
-	dataDir := filepath.Join(homeDir, ".brain")
 	notesPath := filepath.Join(dataDir, "notes.json")
 
 	// Create data directory if it doesn't exist
@@ -46,15 +97,24 @@ func New() (*Brain, error) {
 		return nil, err
 	}
 
+	config, err := LoadConfig(configPath(dataDir))
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize embedder (using OpenAI by default, can be configured)
-	embedder, err := NewOpenAIEmbedder()
+	embedder, err := newEmbedder(config)
 	if err != nil {
-		// Fall back to local embedder if OpenAI fails
-		embedder = NewLocalEmbedder()
+		return nil, err
+	}
+
+	synonyms, err := loadSynonyms(synonymsPath(dataDir))
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize vector store
-	vectorStore, err := NewSimpleVectorStore(dataDir)
+	vectorStore, err := NewSimpleVectorStore(dataDir, config.VectorStore)
 	if err != nil {
 		return nil, err
 	}
@@ -62,8 +122,14 @@ func New() (*Brain, error) {
 	b := &Brain{
 		dataDir:     dataDir,
 		notesPath:   notesPath,
+		config:      config,
 		embedder:    embedder,
 		vectorStore: vectorStore,
+		keywords:    newKeywordIndex(),
+		synonyms:    synonyms,
+		llm:         newLLM(config.LLM),
+		prompts:     loadPrompts(promptsPath(dataDir)),
+		usage:       &usageLog{path: usagePath(dataDir), config: config.Usage},
 	}
 
 	// Load existing notes into vector store
@@ -80,37 +146,178 @@ func (b *Brain) AddNote(note *Note) error {
 		note.ID = uuid.New().String()
 	}
 
-	// Generate embedding
-	embedding, err := b.embedder.Embed(note.Content)
-	if err != nil {
-		return fmt.Errorf("failed to generate embedding: %w", err)
-	}
-	note.Embedding = embedding
+	// Generate embedding. If the embedder is unavailable the note is still
+	// saved and queued as pending, so nothing the user wrote is lost.
+	embedErr := b.embedNote(note)
 
 	// Add to vector store
 	if err := b.vectorStore.Add(note); err != nil {
 		return err
 	}
+	b.keywords.Add(note)
 
 	// Save to disk
-	return b.saveNotes()
+	if err := b.saveNotes(); err != nil {
+		return err
+	}
+
+	if embedErr != nil {
+		return fmt.Errorf("%w: %w", ErrEmbeddingPending, embedErr)
+	}
+	return nil
+}
+
+// Config returns the loaded settings
+func (b *Brain) Config() *Config {
+	return b.config
 }
 
 func (b *Brain) Search(query string, limit int, tags []string) ([]SearchResult, error) {
-	// Generate embedding for query
-	embedding, err := b.embedder.Embed(query)
+	return b.SearchWithOptions(query, SearchOptions{
+		Limit:     limit,
+		Tags:      tags,
+		MinScore:  b.config.Search.MinScore,
+		PlainText: true,
+	})
+}
+
+func (b *Brain) GetContextualNotes(ctx interface{}) ([]SearchResult, error) {
+	// Type assert to get our Context struct
+	context, ok := ctx.(Context)
+	if !ok {
+		// Fallback if wrong type
+		return b.Search("context", 5, nil)
+	}
+
+	return b.GetContextualNotesWithOptions(context, SearchOptions{
+		Limit:    5,
+		MinScore: b.config.Search.MinScore,
+	})
+}
+
+// GetContextualNotesWithOptions searches for notes relevant to the context.
+// Limit and MinScore apply after same-project notes are boosted.
+func (b *Brain) GetContextualNotesWithOptions(context Context, opts SearchOptions) ([]SearchResult, error) {
+	// Build a query from context information
+	queryParts := []string{context.Project, context.Description}
+	queryParts = append(queryParts, context.Keywords...)
+	
+	// Filter out empty strings
+	var validParts []string
+	for _, part := range queryParts {
+		if part != "" {
+			validParts = append(validParts, part)
+		}
+	}
+	
+	if len(validParts) == 0 {
+		return []SearchResult{}, nil
+	}
+	
+	// Join all parts into a single query string
+	query := strings.Join(validParts, " ")
+	
+	// Rank every note by the constructed query, so a boosted project note
+	// just outside the limit isn't lost. It's plain text, not query syntax.
+	ranked, err := b.rankText(query, opts.Mode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		return nil, err
+	}
+	b.config.Ranking.rank(ranked, time.Now())
+	
+	filter := TagFilter{Any: opts.Tags, All: opts.AllTags, Exclude: opts.ExcludeTags}
+	var results []SearchResult
+	for _, r := range ranked {
+		if filter.Matches(r.Note.Tags) {
+			results = append(results, r)
+		}
 	}
+	
+	// If we have a project name, boost results that match it
+	if context.Project != "" {
+		for i := range results {
+			if results[i].Note.Project == context.Project {
+				boosted := results[i].Similarity * 1.2 // 20% boost for same project
+				if boosted > 1.0 {
+					boosted = 1.0 // Cap at 1.0
+				}
+				results[i].Score.ProjectBoost = boosted - results[i].Similarity
+				results[i].Similarity = boosted
+			}
+		}
+		
+		// Re-sort after boosting
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Similarity > results[j].Similarity
+		})
+	}
+	
+	results = dropBelow(results, opts.MinScore)
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	
+	return results, nil
+}
 
-	// Search vector store
-	return b.vectorStore.Search(embedding, limit, tags)
+// FindNote returns the note with the given ID or unique ID prefix
+func (b *Brain) FindNote(id string) (*Note, error) {
+	var found *Note
+	for _, note := range b.vectorStore.GetAllNotes() {
+		if note.ID == id {
+			return note, nil
+		}
+		if id != "" && strings.HasPrefix(note.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("note ID %q is ambiguous", id)
+			}
+			found = note
+		}
+	}
+	
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+	}
+	return found, nil
 }
 
-func (b *Brain) GetContextualNotes(ctx interface{}) ([]SearchResult, error) {
-	// TODO: Implement context-aware search
-	// For now, just search based on project name
-	return b.Search("context", 5, nil)
+func (b *Brain) ListNotes(tags []string) ([]*Note, error) {
+	notes, _, err := b.ListNotesWithOptions(ListOptions{Filter: TagFilter{Any: tags}})
+	return notes, err
+}
+
+// ListOptions controls ListNotesWithOptions
+type ListOptions struct {
+	Filter TagFilter
+	Saved  string // list only notes matched by this saved search
+	Offset int    // skip this many notes, for paging
+	Limit  int    // 0 means all
+}
+
+// ListNotesWithOptions returns one page of the notes passing the filter,
+// most recent first, and how many notes passed it in total
+func (b *Brain) ListNotesWithOptions(opts ListOptions) ([]*Note, int, error) {
+	allNotes := b.vectorStore.GetAllNotes()
+	if opts.Saved != "" {
+		var err error
+		if allNotes, err = b.Collection(opts.Saved); err != nil {
+			return nil, 0, err
+		}
+	}
+	
+	var filtered []*Note
+	for _, note := range allNotes {
+		if opts.Filter.Matches(note.Tags) {
+			filtered = append(filtered, note)
+		}
+	}
+	
+	// Sort by timestamp, most recent first
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Timestamp.After(filtered[j].Timestamp)
+	})
+	
+	return paginate(filtered, opts.Offset, opts.Limit), len(filtered), nil
 }
 
 func (b *Brain) loadNotes() error {
@@ -129,20 +336,50 @@ func (b *Brain) loadNotes() error {
 		return err
 	}
 
-	// Load each note into vector store
+	// Clear the vector store first to avoid duplicates
+	vectorStore, err := NewSimpleVectorStore(b.dataDir, b.config.VectorStore)
+	if err != nil {
+		return err
+	}
+	b.vectorStore = vectorStore
+	b.keywords = newKeywordIndex()
+
+	// Reuse the embeddings saved last time, so only new or changed notes
+	// are embedded, or all of them when the embedder has changed
+	saved := loadEmbeddings(embeddingsPath(b.dataDir), embedderID(b.embedder))
+	restored := 0
+
+	// Load each note into vector store. Notes we can't embed are kept as
+	// pending so they still get listed and saved.
+	embedderDown := false
 	for _, note := range notes {
+		if entry, ok := saved[note.ID]; ok && entry.matches(note) {
+			vectorStore.restore(note, entry)
+			b.keywords.Add(note)
+			note.Pending = false
+			restored++
+			continue
+		}
+
 		// Generate embedding if not present
 		if len(note.Embedding) == 0 {
-			embedding, err := b.embedder.Embed(note.Content)
-			if err != nil {
-				continue // Skip notes we can't embed
+			if embedderDown {
+				note.Pending = true
+			} else if err := b.embedNote(note); err != nil && (isRetryable(err) || errors.Is(err, ErrBudgetExceeded)) {
+				// Don't wait on an unavailable or over budget embedder once
+				// per note
+				embedderDown = true
 			}
-			note.Embedding = embedding
 		}
 		
 		b.vectorStore.Add(note)
+		b.keywords.Add(note)
 	}
 
+	// Failing to update the cache only means embedding again next time
+	if b.embeddingsChanged || restored < len(saved) {
+		b.saveEmbeddings()
+	}
 	return nil
 }
 
@@ -154,5 +391,12 @@ func (b *Brain) saveNotes() error {
 		return err
 	}
 
-	return os.WriteFile(b.notesPath, data, 0644)
+	if err := os.WriteFile(b.notesPath, data, 0644); err != nil {
+		return err
+	}
+
+	if b.embeddingsChanged {
+		return b.saveEmbeddings()
+	}
+	return nil
 }
@@ -1,6 +1,9 @@
 package brain
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -77,6 +80,24 @@ func TestHasAnyTag(t *testing.T) {
 			filterTags: []string{},
 			expected:   false,
 		},
+		{
+			name:       "parent tag matches descendant",
+			noteTags:   []string{"lang/go/concurrency"},
+			filterTags: []string{"lang/go"},
+			expected:   true,
+		},
+		{
+			name:       "prefix that is not a parent",
+			noteTags:   []string{"lang/golang"},
+			filterTags: []string{"lang/go"},
+			expected:   false,
+		},
+		{
+			name:       "child tag does not match parent",
+			noteTags:   []string{"lang/go"},
+			filterTags: []string{"lang/go/concurrency"},
+			expected:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,3 +194,116 @@ func TestLocalEmbedder(t *testing.T) {
 		}
 	}
 }
+
+// newTestBrain creates a Brain backed by a temporary data directory
+func newTestBrain(t *testing.T, embedder Embedder) *Brain {
+	t.Helper()
+
+	dataDir := t.TempDir()
+	return &Brain{
+		dataDir:     dataDir,
+		notesPath:   filepath.Join(dataDir, "notes.json"),
+		config:      DefaultConfig(),
+		embedder:    embedder,
+		vectorStore: &SimpleVectorStore{notes: make([]*Note, 0)},
+		keywords:    newKeywordIndex(),
+	}
+}
+
+func TestAddNoteQueuesPendingEmbedding(t *testing.T) {
+	b := newTestBrain(t, &flakyEmbedder{errs: []error{&APIError{StatusCode: 503}}})
+
+	note := &Note{Content: "Written while offline", Timestamp: time.Now()}
+	err := b.AddNote(note)
+	if !errors.Is(err, ErrEmbeddingPending) {
+		t.Fatalf("Expected ErrEmbeddingPending, got %v", err)
+	}
+
+	// The note must be saved even though it couldn't be embedded
+	if err := b.loadNotes(); err != nil {
+		t.Fatalf("Failed to reload notes: %v", err)
+	}
+	notes, _ := b.ListNotes(nil)
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 saved note, got %d", len(notes))
+	}
+
+	// Reloading retried the embedding, which now succeeds
+	if len(b.PendingNotes()) != 0 {
+		t.Errorf("Expected no pending notes after reload, got %d", len(b.PendingNotes()))
+	}
+}
+
+func TestEmbedPending(t *testing.T) {
+	b := newTestBrain(t, &flakyEmbedder{})
+	b.vectorStore.Add(&Note{ID: "pending", Content: "Needs embedding", Pending: true})
+	b.vectorStore.Add(&Note{ID: "done", Content: "Already embedded", Embedding: []float32{1, 0, 0}})
+
+	// Pending notes are never returned by search
+	results, _ := b.vectorStore.Search([]float32{1, 0, 0}, 10, nil)
+	if len(results) != 1 || results[0].Note.ID != "done" {
+		t.Fatalf("Expected only the embedded note in results, got %d", len(results))
+	}
+
+	embedded, err := b.EmbedPending()
+	if err != nil {
+		t.Fatalf("EmbedPending failed: %v", err)
+	}
+	if embedded != 1 {
+		t.Errorf("Expected 1 embedded note, got %d", embedded)
+	}
+	if len(b.PendingNotes()) != 0 {
+		t.Errorf("Expected no pending notes, got %d", len(b.PendingNotes()))
+	}
+}
+
+func TestPagination(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		note := &Note{
+			Content:   fmt.Sprintf("Deploy runbook step %d", i),
+			Tags:      []string{"deploy"},
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	notes, total, err := b.ListNotesWithOptions(ListOptions{Offset: 3, Limit: 3})
+	if err != nil {
+		t.Fatalf("ListNotesWithOptions failed: %v", err)
+	}
+	if total != 7 || len(notes) != 3 || notes[0].Content != "Deploy runbook step 3" {
+		t.Errorf("Expected notes 4-6 of 7 newest first, got %d of %d starting %q", len(notes), total, notes[0].Content)
+	}
+
+	if notes, _, _ := b.ListNotesWithOptions(ListOptions{Offset: 6, Limit: 3}); len(notes) != 1 {
+		t.Errorf("Expected a short last page, got %d notes", len(notes))
+	}
+	if notes, _, _ := b.ListNotesWithOptions(ListOptions{Offset: 10, Limit: 3}); len(notes) != 0 {
+		t.Errorf("Expected no notes past the end, got %d", len(notes))
+	}
+
+	all, err := b.SearchWithOptions("deploy runbook", SearchOptions{Limit: 7})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	var paged []SearchResult
+	for offset := 0; offset < len(all); offset += 3 {
+		page, err := b.SearchWithOptions("deploy runbook", SearchOptions{Limit: 3, Offset: offset})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		paged = append(paged, page...)
+	}
+	if len(paged) != len(all) {
+		t.Fatalf("Expected pages to add up to %d results, got %d", len(all), len(paged))
+	}
+	for i := range all {
+		if paged[i].Note != all[i].Note {
+			t.Errorf("Result %d differs between paged and unpaged search", i)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Chat with your notes",
+	Long: `Start a conversation with your notes. Each question is answered from
+the notes most relevant to it and the questions before it, so follow-ups
+like "and what about Redis?" work. Conversations are saved and can be
+resumed later.
+
+Commands inside a chat:
+  /add [tags]   save the last answer as a note, e.g. /add postgres,tuning
+  /sources      show the notes behind the last answer again
+  /help         list these commands
+  /quit         leave the chat (or press Ctrl-D)
+
+Press Ctrl-C while an answer is printing to stop it.
+
+Examples:
+  brain chat
+  brain chat --continue          # resume the most recent chat
+  brain chat --list
+  brain chat --resume 3f2a9c1e`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, _ := cmd.Flags().GetBool("list")
+		resume, _ := cmd.Flags().GetString("resume")
+		resumeLast, _ := cmd.Flags().GetBool("continue")
+		limit, _ := cmd.Flags().GetInt("limit")
+		grounded, _ := cmd.Flags().GetBool("grounded")
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		if list {
+			return listChats(b)
+		}
+
+		if !b.HasLLM() {
+			return fmt.Errorf("%w: set OPENAI_API_KEY, or configure llm: in ~/.brain/config.yaml, to chat", brain.ErrNoLLM)
+		}
+
+		session := brain.NewChatSession()
+		if resume != "" || resumeLast {
+			if session, err = b.ChatSession(resume); err != nil {
+				return err
+			}
+			fmt.Printf("💬 Resuming \"%s\" (%s)\n\n", session.Title, brain.ShortID(session.ID))
+			for _, turn := range session.Turns {
+				fmt.Printf("> %s\n💡 %s\n\n", turn.Question, turn.Answer)
+			}
+		} else {
+			fmt.Printf("💬 Chatting with your notes (%s). /help for commands, Ctrl-D to quit.\n\n", brain.ShortID(session.ID))
+		}
+
+		workingContext := detectContext()
+		c := &chat{
+			b:        b,
+			session:  session,
+			opts:     brain.SearchOptions{Limit: limit, MinScore: minScore(cmd, b)},
+			grounded: grounded || b.Config().LLM.Grounded,
+			context:  &workingContext,
+		}
+		return c.run(cmd, os.Stdin)
+	},
+}
+
+// chat is a running brain chat session
+type chat struct {
+	b        *brain.Brain
+	session  *brain.ChatSession
+	opts     brain.SearchOptions
+	grounded bool
+	context  *brain.Context // where the chat runs, for the prompt
+	last     *brain.Answer  // the latest answer, for /add and /sources
+}
+
+func (c *chat) run(cmd *cobra.Command, in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Print("> ")
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Println()
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/"):
+			if quit := c.command(line); quit {
+				return nil
+			}
+		default:
+			c.ask(cmd, line)
+		}
+	}
+}
+
+// ask answers one question. Failures are reported without ending the
+// chat, and Ctrl-C cancels just the answer.
+func (c *chat) ask(cmd *cobra.Command, question string) {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	out := &tokenPrinter{}
+	answer, err := c.b.Chat(ctx, c.session, question, c.opts, brain.AnswerOptions{
+		Grounded: c.grounded,
+		OnToken:  out.print,
+		Context:  c.context,
+	})
+	out.finish()
+	switch {
+	case errors.Is(err, brain.ErrNoRelevantNotes):
+		fmt.Println(brain.NoAnswer)
+		fmt.Println()
+		return
+	case errors.Is(err, context.Canceled):
+		fmt.Println("⚠ Cancelled, the question wasn't added to the chat")
+		fmt.Println()
+		return
+	case answer == nil:
+		fmt.Printf("⚠ %v\n\n", explainError(err))
+		return
+	}
+
+	printSources(answer, false)
+	if c.grounded {
+		printGroundingCheck(answer)
+		fmt.Println()
+	}
+	if err != nil {
+		fmt.Printf("⚠ %v\n\n", err)
+	}
+	recordShown(c.b, answer.Sources)
+	c.last = answer
+}
+
+// command runs a /command and reports whether the chat should end
+func (c *chat) command(line string) bool {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/quit", "/exit":
+		return true
+	case "/help":
+		fmt.Println("/add [tags]   save the last answer as a note")
+		fmt.Println("/sources      show the notes behind the last answer")
+		fmt.Println("/quit         leave the chat")
+	case "/sources":
+		if c.last == nil {
+			fmt.Println("No answer yet, ask something first.")
+		} else {
+			printSources(c.last, false)
+			return false
+		}
+	case "/add":
+		c.addNote(fields[1:])
+	default:
+		fmt.Printf("Unknown command %s, try /help\n", fields[0])
+	}
+	fmt.Println()
+	return false
+}
+
+// addNote saves the last question and answer as a note, with citations
+// turned into @id links to the notes it came from
+func (c *chat) addNote(tagArgs []string) {
+	if c.last == nil {
+		fmt.Println("No answer to save yet, ask something first.")
+		return
+	}
+
+	var tags []string
+	for _, arg := range tagArgs {
+		for _, tag := range strings.Split(arg, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	question := c.session.Turns[len(c.session.Turns)-1].Question
+	note := &brain.Note{
+		Content:   question + "\n\n" + c.last.WithMentions(),
+		Tags:      tags,
+		Timestamp: time.Now(),
+	}
+	if err := c.b.AddNote(note); errors.Is(err, brain.ErrEmbeddingPending) {
+		fmt.Printf("⚠ Saved, but the note won't show up in searches until it's embedded (ID: %s)\n", note.ID)
+		return
+	} else if err != nil {
+		fmt.Printf("⚠ Failed to add note: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ Saved the answer as a note (ID: %s)\n", note.ID)
+}
+
+// listChats prints the saved chat sessions
+func listChats(b *brain.Brain) error {
+	sessions, err := b.ChatSessions()
+	if err != nil {
+		return fmt.Errorf("failed to read chats: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No saved chats yet. Start one with: brain chat")
+		return nil
+	}
+
+	for _, s := range sessions {
+		fmt.Printf("%s  %s  %2d turn(s)  %s\n", brain.ShortID(s.ID), s.Updated.Format("2006-01-02 15:04"), len(s.Turns), s.Title)
+	}
+	fmt.Println("\nResume one with: brain chat --resume <id>")
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(chatCmd)
+	chatCmd.Flags().Bool("list", false, "List saved chats")
+	chatCmd.Flags().String("resume", "", "Resume the chat with this ID (or unique prefix)")
+	chatCmd.Flags().Bool("continue", false, "Resume the most recent chat")
+	chatCmd.Flags().IntP("limit", "l", 5, "Maximum number of notes to retrieve per question")
+	chatCmd.Flags().Float64("min-score", 0, "Drop notes scoring below this (0-1, default from config)")
+	chatCmd.Flags().Bool("grounded", false, "Require a cited note for every statement and check the citations")
+}
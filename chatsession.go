@@ -0,0 +1,223 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrChatNotFound is returned when no chat session has the requested ID
+	ErrChatNotFound = errors.New("chat session not found")
+
+	// ErrNoRelevantNotes is returned when no note is relevant enough to
+	// answer a question
+	ErrNoRelevantNotes = errors.New("no notes relevant to the question")
+)
+
+const (
+	// chatRetrievalTurns is how many earlier questions are searched along
+	// with the new one, so follow-ups like "and for Redis?" find notes
+	chatRetrievalTurns = 2
+
+	// chatHistoryTurns is how many earlier turns are sent to the model
+	chatHistoryTurns = 10
+)
+
+// chatHistoryPrompt is added to the default system prompt in chats
+const chatHistoryPrompt = `
+Earlier messages are the conversation so far. Citations in earlier answers refer to the notes given with that question.`
+
+// ChatSession is a conversation with the notes, kept in the chats
+// directory so it can be resumed
+type ChatSession struct {
+	ID      string     `json:"id"`
+	Title   string     `json:"title"` // the first question
+	Created time.Time  `json:"created"`
+	Updated time.Time  `json:"updated"`
+	Turns   []ChatTurn `json:"turns"`
+}
+
+// ChatTurn is one question and its answer
+type ChatTurn struct {
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+	Sources  []string  `json:"sources,omitempty"` // IDs of the notes used; [n] cites Sources[n-1]
+	Time     time.Time `json:"time"`
+}
+
+// NewChatSession starts an empty conversation
+func NewChatSession() *ChatSession {
+	now := time.Now()
+	return &ChatSession{ID: uuid.New().String(), Created: now, Updated: now}
+}
+
+func (b *Brain) chatsDir() string {
+	return filepath.Join(b.dataDir, "chats")
+}
+
+// Chat answers the next question of a conversation. Notes are retrieved
+// again each turn using the latest questions, and the earlier turns are
+// sent as history. The turn is added to the session, which is saved.
+func (b *Brain) Chat(ctx context.Context, session *ChatSession, question string, opts SearchOptions, answerOpts AnswerOptions) (*Answer, error) {
+	if b.llm == nil {
+		return nil, ErrNoLLM
+	}
+
+	queries := []string{question}
+	for i := len(session.Turns) - 1; i >= 0 && len(queries) <= chatRetrievalTurns; i-- {
+		queries = append(queries, session.Turns[i].Question)
+	}
+	results, err := b.searchText(strings.Join(queries, "\n"), opts)
+	if err != nil {
+		return nil, err
+	}
+	// Follow-ups may be answered from the history alone, unless answers
+	// must be grounded in notes
+	results = b.groundingResults(results, answerOpts)
+	if len(results) == 0 && (len(session.Turns) == 0 || answerOpts.Grounded) {
+		return nil, ErrNoRelevantNotes
+	}
+
+	notes, sources := packNotes(results, b.config.LLM.ContextTokens)
+	messages, err := b.prompts.chatMessages(session.Turns, answerOpts.promptData(question, notes))
+	if err != nil {
+		return nil, err
+	}
+	completion, err := b.complete(ctx, messages, answerOpts.OnToken)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &Answer{
+		Text:    completion.Content,
+		Sources: sources,
+		Model:   completion.Model,
+		Usage:   completion.Usage,
+	}
+
+	turn := ChatTurn{Question: question, Answer: answer.Text, Time: time.Now()}
+	for _, r := range sources {
+		turn.Sources = append(turn.Sources, r.Note.ID)
+	}
+	if session.Title == "" {
+		session.Title = FirstLine(question)
+	}
+	session.Turns = append(session.Turns, turn)
+	session.Updated = turn.Time
+
+	if err := b.SaveChatSession(session); err != nil {
+		return answer, fmt.Errorf("failed to save the chat session: %w", err)
+	}
+	return answer, nil
+}
+
+// chatMessages builds the conversation for the next turn: the recent
+// history between the system prompt and the new question with this
+// turn's notes
+func (p *promptSet) chatMessages(history []ChatTurn, data promptData) ([]Message, error) {
+	if len(history) > chatHistoryTurns {
+		history = history[len(history)-chatHistoryTurns:]
+	}
+
+	prompt, err := p.render("chat", data)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]Message, 0, len(history)*2+2)
+	messages = append(messages, prompt[0])
+	for _, turn := range history {
+		messages = append(messages,
+			Message{Role: "user", Content: turn.Question},
+			Message{Role: "assistant", Content: turn.Answer},
+		)
+	}
+	return append(messages, prompt[1]), nil
+}
+
+// SaveChatSession writes a session to the chats directory
+func (b *Brain) SaveChatSession(session *ChatSession) error {
+	if err := os.MkdirAll(b.chatsDir(), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.chatsDir(), session.ID+".json"), data, 0644)
+}
+
+// ChatSessions returns the saved sessions, most recently used first
+func (b *Brain) ChatSessions() ([]*ChatSession, error) {
+	entries, err := os.ReadDir(b.chatsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*ChatSession
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(b.chatsDir(), entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var session ChatSession
+		if err := json.Unmarshal(data, &session); err != nil {
+			return nil, fmt.Errorf("invalid chat session %s: %w", path, err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+	return sessions, nil
+}
+
+// ChatSession returns the session with the given ID or unique ID prefix.
+// An empty ID means the most recently used session.
+func (b *Brain) ChatSession(id string) (*ChatSession, error) {
+	sessions, err := b.ChatSessions()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		if len(sessions) == 0 {
+			return nil, fmt.Errorf("%w: there are no saved chats yet", ErrChatNotFound)
+		}
+		return sessions[0], nil
+	}
+
+	var found *ChatSession
+	for _, session := range sessions {
+		if session.ID == id {
+			return session, nil
+		}
+		if strings.HasPrefix(session.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("chat ID %q is ambiguous", id)
+			}
+			found = session
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrChatNotFound, id)
+	}
+	return found, nil
+}
@@ -0,0 +1,93 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChat(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "Size the pool to the core count [1].")
+
+	session := NewChatSession()
+	opts := SearchOptions{Limit: 5, MinScore: 0.1}
+
+	if _, err := b.Chat(context.Background(), session, "How big should the pool be?", opts, AnswerOptions{}); !errors.Is(err, ErrNoLLM) {
+		t.Fatalf("Expected ErrNoLLM without a model, got %v", err)
+	}
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	if _, err := b.Chat(context.Background(), session, "How big should the pool be?", opts, AnswerOptions{}); !errors.Is(err, ErrNoRelevantNotes) {
+		t.Fatalf("Expected ErrNoRelevantNotes without notes, got %v", err)
+	}
+
+	pool := &Note{Content: "Postgres connection pool size should match the cores", Timestamp: time.Now()}
+	redis := &Note{Content: "Redis maxclients defaults to 10000", Timestamp: time.Now()}
+	for _, note := range []*Note{pool, redis} {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	answer, err := b.Chat(context.Background(), session, "How big should the postgres connection pool be?", opts, AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if len(answer.Sources) == 0 || answer.Sources[0].Note != pool {
+		t.Fatalf("Expected the pool note as the first source")
+	}
+
+	// A follow-up is answered with the earlier turn as history
+	if _, err := b.Chat(context.Background(), session, "And for redis?", opts, AnswerOptions{}); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if len(last.Messages) != 4 {
+		t.Fatalf("Expected system, one earlier turn and the question, got %d messages", len(last.Messages))
+	}
+	if last.Messages[1].Content != "How big should the postgres connection pool be?" || last.Messages[2].Role != "assistant" {
+		t.Errorf("Expected the first turn as history, got %+v", last.Messages[1:3])
+	}
+	if !strings.Contains(last.Messages[3].Content, "Redis maxclients") {
+		t.Errorf("Expected the redis note to be retrieved for the follow-up")
+	}
+
+	// The session is saved and can be resumed by ID prefix or as the latest
+	for _, id := range []string{session.ID[:6], ""} {
+		resumed, err := b.ChatSession(id)
+		if err != nil {
+			t.Fatalf("ChatSession(%q) failed: %v", id, err)
+		}
+		if resumed.Title != "How big should the postgres connection pool be?" || len(resumed.Turns) != 2 {
+			t.Errorf("Unexpected resumed session %q with %d turns", resumed.Title, len(resumed.Turns))
+		}
+		if resumed.Turns[0].Sources[0] != pool.ID {
+			t.Errorf("Expected the first turn's sources to be saved")
+		}
+	}
+
+	if _, err := b.ChatSession("ffffffff"); !errors.Is(err, ErrChatNotFound) {
+		t.Errorf("Expected ErrChatNotFound, got %v", err)
+	}
+}
+
+func TestChatMessagesHistoryLimit(t *testing.T) {
+	var history []ChatTurn
+	for i := 0; i < chatHistoryTurns+3; i++ {
+		history = append(history, ChatTurn{Question: "q", Answer: "a"})
+	}
+
+	var prompts *promptSet
+	messages, err := prompts.chatMessages(history, promptData{Question: "next"})
+	if err != nil {
+		t.Fatalf("chatMessages failed: %v", err)
+	}
+	if want := 1 + 2*chatHistoryTurns + 1; len(messages) != want {
+		t.Errorf("Expected %d messages, got %d", want, len(messages))
+	}
+	if !strings.Contains(messages[len(messages)-1].Content, "no notes matched") {
+		t.Errorf("Expected a note that nothing matched, got %q", messages[len(messages)-1].Content)
+	}
+}
@@ -0,0 +1,227 @@
+package brain
+
+import (
+	"math"
+	"strings"
+)
+
+// Long notes are split into overlapping passages which are embedded
+// separately, so a single matching section isn't drowned out by the rest
+// of the note and we stay well inside provider token limits.
+const (
+	maxChunkChars     = 1200 // roughly 300 tokens
+	chunkOverlapChars = 200
+)
+
+// Chunk is a passage of a long note with its own embedding
+type Chunk struct {
+	Text      string
+	Embedding []float32
+}
+
+// splitIntoChunks splits content into passages of at most maxChars,
+// preferring paragraph and heading boundaries. Consecutive chunks share up
+// to overlap characters of trailing paragraphs, and chunks that start in
+// the middle of a section repeat its heading for context.
+func splitIntoChunks(content string, maxChars, overlap int) []string {
+	content = strings.TrimSpace(content)
+	if len(content) <= maxChars {
+		return []string{content}
+	}
+
+	var chunks []string
+	var current []string
+	size := 0
+	heading := ""
+
+	// Sizes include the blank line each block is joined with
+	add := func(block string) {
+		current = append(current, block)
+		size += len(block) + 2
+	}
+	flush := func(tail []string) {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, "\n\n"))
+		}
+		current, size = nil, 0
+		if heading != "" && (len(tail) == 0 || tail[0] != heading) {
+			add(heading)
+		}
+		for _, block := range tail {
+			add(block)
+		}
+	}
+
+	// Leave room for a repeated heading when splitting long paragraphs
+	for _, block := range splitBlocks(content, maxChars-overlap) {
+		if isHeading(block) {
+			// Start each section in a fresh chunk unless the current one is small
+			if size >= maxChars/2 {
+				heading = ""
+				flush(nil)
+			}
+			heading = block
+		} else if size+len(block) > maxChars {
+			flush(overlapTail(current, overlap))
+			if size+len(block) > maxChars {
+				// The overlap doesn't leave room for this block
+				current, size = nil, 0
+				flush(nil)
+			}
+		}
+
+		add(block)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, "\n\n"))
+	}
+
+	return chunks
+}
+
+// splitBlocks breaks content into paragraphs and headings, splitting any
+// paragraph longer than maxChars at sentence and then word boundaries
+func splitBlocks(content string, maxChars int) []string {
+	var blocks []string
+	var paragraph []string
+
+	endParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, splitLongText(strings.Join(paragraph, "\n"), maxChars)...)
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			endParagraph()
+		case isHeading(trimmed):
+			endParagraph()
+			blocks = append(blocks, trimmed)
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	endParagraph()
+
+	return blocks
+}
+
+func isHeading(block string) bool {
+	return strings.HasPrefix(block, "#") && !strings.Contains(block, "\n")
+}
+
+// splitLongText packs sentences (or words, for very long sentences) into
+// pieces of at most maxChars
+func splitLongText(text string, maxChars int) []string {
+	if len(text) <= maxChars {
+		return []string{text}
+	}
+	if maxChars < 1 {
+		maxChars = 1
+	}
+
+	var pieces []string
+	var current strings.Builder
+
+	add := func(part string) {
+		if current.Len() > 0 && current.Len()+1+len(part) > maxChars {
+			pieces = append(pieces, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+		current.WriteString(part)
+	}
+
+	for _, sentence := range splitSentences(text) {
+		if len(sentence) <= maxChars {
+			add(sentence)
+			continue
+		}
+		for _, word := range strings.Fields(sentence) {
+			for len(word) > maxChars {
+				add(word[:maxChars])
+				word = word[maxChars:]
+			}
+			add(word)
+		}
+	}
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+
+	return pieces
+}
+
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '.', '!', '?':
+			if i+1 < len(text) && (text[i+1] == ' ' || text[i+1] == '\n') {
+				sentences = append(sentences, strings.TrimSpace(text[start:i+1]))
+				start = i + 1
+			}
+		case '\n':
+			sentences = append(sentences, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	sentences = append(sentences, strings.TrimSpace(text[start:]))
+
+	// Drop empty pieces left by consecutive separators
+	result := sentences[:0]
+	for _, s := range sentences {
+		if s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// overlapTail returns the trailing blocks that fit within overlap characters,
+// never the whole chunk
+func overlapTail(blocks []string, overlap int) []string {
+	size := 0
+	start := len(blocks)
+	for start > 1 && size+len(blocks[start-1]) <= overlap {
+		start--
+		size += len(blocks[start])
+	}
+	return blocks[start:]
+}
+
+// meanEmbedding averages chunk embeddings into a single normalized vector
+// that represents the whole note
+func meanEmbedding(chunks []Chunk) []float32 {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	mean := make([]float32, len(chunks[0].Embedding))
+	for _, chunk := range chunks {
+		for i := range mean {
+			if i < len(chunk.Embedding) {
+				mean[i] += chunk.Embedding[i]
+			}
+		}
+	}
+
+	var sum float64
+	for _, v := range mean {
+		sum += float64(v * v)
+	}
+	if sum > 0 {
+		norm := float32(1.0 / math.Sqrt(sum))
+		for i := range mean {
+			mean[i] *= norm
+		}
+	}
+
+	return mean
+}
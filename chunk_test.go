@@ -0,0 +1,85 @@
+package brain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitIntoChunks(t *testing.T) {
+	paragraph := strings.Repeat("Restart the worker pool before draining the queue. ", 8)
+	runbook := "# Deploy\n\n" + paragraph + "\n\n" + paragraph + "\n\n" +
+		"# Rollback\n\n" + paragraph + "\n\n" + paragraph
+
+	tests := []struct {
+		name      string
+		content   string
+		minChunks int
+		maxChunks int
+	}{
+		{
+			name:      "short note is a single chunk",
+			content:   "Use context.WithTimeout for API calls",
+			minChunks: 1,
+			maxChunks: 1,
+		},
+		{
+			name:      "runbook splits on sections",
+			content:   runbook,
+			minChunks: 2,
+			maxChunks: 4,
+		},
+		{
+			name:      "single huge paragraph",
+			content:   strings.Repeat("word ", 1000),
+			minChunks: 5,
+			maxChunks: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitIntoChunks(tt.content, maxChunkChars, chunkOverlapChars)
+			if len(chunks) < tt.minChunks || len(chunks) > tt.maxChunks {
+				t.Errorf("Expected %d-%d chunks, got %d", tt.minChunks, tt.maxChunks, len(chunks))
+			}
+			for i, chunk := range chunks {
+				if len(chunk) > maxChunkChars {
+					t.Errorf("Chunk %d is %d chars, want at most %d", i, len(chunk), maxChunkChars)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitIntoChunksKeepsHeadings(t *testing.T) {
+	paragraph := strings.Repeat("Check the replication lag on the replica first. ", 10)
+	content := "# Failover\n\n" + paragraph + "\n\n" + paragraph + "\n\n" + paragraph
+
+	chunks := splitIntoChunks(content, maxChunkChars, chunkOverlapChars)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected the section to span several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk, "# Failover") {
+			t.Errorf("Chunk %d doesn't start with its section heading", i)
+		}
+	}
+}
+
+func TestNoteSimilarityUsesBestChunk(t *testing.T) {
+	note := &Note{
+		Embedding: []float32{0.5, 0.5, 0},
+		Chunks: []Chunk{
+			{Text: "first", Embedding: []float32{0, 1, 0}},
+			{Text: "second", Embedding: []float32{1, 0, 0}},
+		},
+	}
+
+	similarity, passage := noteSimilarity([]float32{1, 0, 0}, note)
+	if passage != "second" {
+		t.Errorf("Expected passage %q, got %q", "second", passage)
+	}
+	if similarity < 0.999 {
+		t.Errorf("Expected similarity 1.0, got %v", similarity)
+	}
+}
@@ -0,0 +1,370 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// kMeansIterations caps the rounds of k-means; it usually settles in
+	// far fewer
+	kMeansIterations = 50
+
+	// clusterTerms is how many characteristic terms label a cluster
+	clusterTerms = 4
+
+	// maxAutoClusters caps the number of clusters picked automatically
+	maxAutoClusters = 20
+
+	// clusterNameNotes is how many notes per cluster the LLM sees
+	clusterNameNotes = 5
+)
+
+const clusterNamePrompt = `You name groups of notes from a personal knowledge base.
+For each numbered group, reply with one line: the group number, a colon and a short name of two to five words.
+Reply with nothing else.`
+
+// clusterNameLine matches "1: Name", "2. Name" or "Group 3 - Name"
+var clusterNameLine = regexp.MustCompile(`(?i)^\s*(?:group\s*)?(\d+)\s*[:.)-]\s*(.+)$`)
+
+// ClusterOptions controls how notes are clustered
+type ClusterOptions struct {
+	// K is the number of clusters; 0 picks one from the number of notes
+	K int
+
+	// Name asks the chat model to name each cluster, if one is configured
+	Name bool
+}
+
+// Cluster is a group of notes with similar embeddings
+type Cluster struct {
+	Terms []string // the most characteristic terms, best first
+	Name  string   // written by the chat model, if asked for
+	Notes []*Note  // closest to the centre first
+
+	// Cohesion is the mean similarity of the notes to the centre
+	Cohesion float64
+}
+
+// Untagged counts the notes in the cluster without tags
+func (c *Cluster) Untagged() int {
+	count := 0
+	for _, note := range c.Notes {
+		if len(note.Tags) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Clusters groups the embedded notes with k-means on their embeddings,
+// largest cluster first, and labels each with its characteristic terms.
+// The clustering is seeded so the same notes give the same clusters.
+func (b *Brain) Clusters(ctx context.Context, opts ClusterOptions) ([]*Cluster, error) {
+	var notes []*Note
+	var vectors [][]float64
+	for _, note := range b.vectorStore.GetAllNotes() {
+		embedding := b.storedEmbedding(note)
+		if len(embedding) == 0 || (len(vectors) > 0 && len(embedding) != len(vectors[0])) {
+			continue
+		}
+		notes = append(notes, note)
+		vectors = append(vectors, unitVector(embedding))
+	}
+
+	k := opts.K
+	if k == 0 {
+		k = autoClusterCount(len(notes))
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("the number of clusters must be positive")
+	}
+	if len(notes) < 2 || len(notes) < k {
+		return nil, fmt.Errorf("%d embedded note(s) aren't enough for %d clusters", len(notes), k)
+	}
+
+	centroids, assignment := kMeans(vectors, k, rand.New(rand.NewSource(1)))
+
+	clusters := make([]*Cluster, k)
+	similarities := make(map[*Note]float64)
+	for i := range clusters {
+		clusters[i] = &Cluster{}
+	}
+	for i, c := range assignment {
+		similarity := dot(vectors[i], centroids[c])
+		similarities[notes[i]] = similarity
+		clusters[c].Notes = append(clusters[c].Notes, notes[i])
+		clusters[c].Cohesion += similarity
+	}
+
+	var result []*Cluster
+	for _, cluster := range clusters {
+		if len(cluster.Notes) == 0 {
+			continue
+		}
+		cluster.Cohesion /= float64(len(cluster.Notes))
+		sort.SliceStable(cluster.Notes, func(i, j int) bool {
+			return similarities[cluster.Notes[i]] > similarities[cluster.Notes[j]]
+		})
+		result = append(result, cluster)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].Notes) > len(result[j].Notes)
+	})
+	labelClusters(result, notes)
+
+	if opts.Name && b.llm != nil {
+		if err := b.nameClusters(ctx, result); err != nil {
+			return result, fmt.Errorf("failed to name the clusters: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// autoClusterCount picks k as the square root of half the notes, the
+// usual rule of thumb
+func autoClusterCount(notes int) int {
+	k := int(math.Round(math.Sqrt(float64(notes) / 2)))
+	if k < 2 {
+		k = 2
+	}
+	if k > maxAutoClusters {
+		k = maxAutoClusters
+	}
+	return k
+}
+
+// kMeans clusters unit vectors by cosine similarity (spherical k-means)
+// with k-means++ seeding. It returns the centroids and the cluster of
+// each vector.
+func kMeans(vectors [][]float64, k int, rng *rand.Rand) ([][]float64, []int) {
+	centroids := seedCentroids(vectors, k, rng)
+	assignment := make([]int, len(vectors))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		changed := false
+		for i, v := range vectors {
+			best, bestSimilarity := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if similarity := dot(v, centroid); similarity > bestSimilarity {
+					best, bestSimilarity = c, similarity
+				}
+			}
+			if assignment[i] != best {
+				assignment[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][]float64, k)
+		for c := range sums {
+			sums[c] = make([]float64, len(vectors[0]))
+		}
+		for i, v := range vectors {
+			for d, x := range v {
+				sums[assignment[i]][d] += x
+			}
+		}
+		for c, sum := range sums {
+			// An empty cluster keeps its old centroid
+			if norm := math.Sqrt(dot(sum, sum)); norm > 0 {
+				centroids[c] = scaleVector(sum, norm)
+			}
+		}
+	}
+
+	return centroids, assignment
+}
+
+// seedCentroids picks k starting centroids, each new one chosen with
+// probability proportional to its squared distance from the nearest one
+// already chosen
+func seedCentroids(vectors [][]float64, k int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{vectors[rng.Intn(len(vectors))]}
+	distances := make([]float64, len(vectors))
+
+	for len(centroids) < k {
+		var total float64
+		for i, v := range vectors {
+			nearest := math.Inf(1)
+			for _, c := range centroids {
+				// For unit vectors the squared distance is 2 - 2·cos
+				nearest = math.Min(nearest, math.Max(0, 2-2*dot(v, c)))
+			}
+			distances[i] = nearest
+			total += nearest
+		}
+
+		next := rng.Intn(len(vectors))
+		if total > 0 {
+			target := rng.Float64() * total
+			for i, d := range distances {
+				if target -= d; target <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, vectors[next])
+	}
+
+	// Copy so updating a centroid never changes a vector
+	for i, c := range centroids {
+		centroids[i] = append([]float64(nil), c...)
+	}
+	return centroids
+}
+
+// labelClusters picks each cluster's characteristic terms: those in many
+// of its notes but few notes overall, a class-based TF-IDF
+func labelClusters(clusters []*Cluster, notes []*Note) {
+	documentFrequency := make(map[string]int)
+	for _, note := range notes {
+		for term := range labelTerms(note.Content) {
+			documentFrequency[term]++
+		}
+	}
+
+	for _, cluster := range clusters {
+		inCluster := make(map[string]int)
+		for _, note := range cluster.Notes {
+			for term := range labelTerms(note.Content) {
+				inCluster[term]++
+			}
+		}
+
+		// In larger clusters a term must be shared to be characteristic
+		minNotes := 1
+		if len(cluster.Notes) > 2 {
+			minNotes = 2
+		}
+
+		scores := make(map[string]float64)
+		for term, count := range inCluster {
+			if count < minNotes {
+				continue
+			}
+			frequency := float64(count) / float64(len(cluster.Notes))
+			scores[term] = frequency * math.Log(1+float64(len(notes))/float64(documentFrequency[term]))
+		}
+
+		terms := make([]string, 0, len(scores))
+		for term := range scores {
+			terms = append(terms, term)
+		}
+		sort.Slice(terms, func(i, j int) bool {
+			if scores[terms[i]] != scores[terms[j]] {
+				return scores[terms[i]] > scores[terms[j]]
+			}
+			return terms[i] < terms[j]
+		})
+		if len(terms) > clusterTerms {
+			terms = terms[:clusterTerms]
+		}
+		cluster.Terms = terms
+	}
+}
+
+// labelTerms returns the distinct terms of text worth labelling a cluster
+// with, leaving out numbers and very short words
+func labelTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, term := range tokenize(text) {
+		if len(term) < 3 || labelStopWords[term] {
+			continue
+		}
+		if _, err := strconv.ParseFloat(term, 64); err == nil {
+			continue
+		}
+		terms[term] = true
+	}
+	return terms
+}
+
+// labelStopWords are common words that say nothing about a topic. Words
+// used across most notes score low anyway; these matter in small brains.
+var labelStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "are": true, "was": true, "but": true,
+	"not": true, "you": true, "use": true, "can": true, "has": true,
+	"have": true, "when": true, "should": true, "into": true, "our": true,
+	"its": true, "all": true, "via": true, "than": true, "then": true,
+}
+
+// nameClusters asks the chat model for a short name for each cluster in
+// one request
+func (b *Brain) nameClusters(ctx context.Context, clusters []*Cluster) error {
+	var prompt strings.Builder
+	for i, cluster := range clusters {
+		fmt.Fprintf(&prompt, "Group %d (terms: %s):\n", i+1, strings.Join(cluster.Terms, ", "))
+		for j, note := range cluster.Notes {
+			if j == clusterNameNotes {
+				break
+			}
+			fmt.Fprintf(&prompt, "- %s\n", truncateRunes(FirstLine(note.Content), 200))
+		}
+		prompt.WriteString("\n")
+	}
+
+	completion, err := b.complete(ctx, []Message{
+		{Role: "system", Content: clusterNamePrompt},
+		{Role: "user", Content: strings.TrimSpace(prompt.String())},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(completion.Content, "\n") {
+		match := clusterNameLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(match[1])
+		if n >= 1 && n <= len(clusters) {
+			clusters[n-1].Name = strings.Trim(strings.TrimSpace(match[2]), `*"'`)
+		}
+	}
+	return nil
+}
+
+// unitVector converts an embedding to float64 scaled to length one
+func unitVector(embedding []float32) []float64 {
+	v := make([]float64, len(embedding))
+	var sum float64
+	for i, x := range embedding {
+		v[i] = float64(x)
+		sum += v[i] * v[i]
+	}
+	if sum == 0 {
+		return v
+	}
+	return scaleVector(v, math.Sqrt(sum))
+}
+
+// scaleVector divides v by norm in place
+func scaleVector(v []float64, norm float64) []float64 {
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
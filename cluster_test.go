@@ -0,0 +1,103 @@
+package brain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func addClusterNotes(t *testing.T, b *Brain) {
+	t.Helper()
+	for _, content := range []string{
+		"Postgres connection pool size should match the cores",
+		"Postgres vacuum settings for busy tables",
+		"Postgres connection limits and pgbouncer pooling",
+		"Kubernetes liveness probes restart stuck pods",
+		"Kubernetes pods get OOM killed under memory pressure",
+		"Kubernetes readiness probes gate traffic to pods",
+	} {
+		if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	addClusterNotes(t, b)
+
+	clusters, err := b.Clusters(context.Background(), ClusterOptions{K: 2})
+	if err != nil {
+		t.Fatalf("Clusters failed: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d", len(clusters))
+	}
+
+	for _, cluster := range clusters {
+		if len(cluster.Notes) != 3 || len(cluster.Terms) == 0 {
+			t.Fatalf("Expected 3 notes and some terms per cluster, got %d and %v", len(cluster.Notes), cluster.Terms)
+		}
+		topic := "postgres"
+		if !containsString(cluster.Terms, topic) {
+			topic = "kubernetes"
+		}
+		if !containsString(cluster.Terms, topic) {
+			t.Fatalf("Expected the topic among the terms, got %v", cluster.Terms)
+		}
+		for _, note := range cluster.Notes {
+			if !containsString(tokenize(note.Content), topic) {
+				t.Errorf("Note %q doesn't belong in the %s cluster", note.Content, topic)
+			}
+		}
+		if cluster.Untagged() != 3 || cluster.Cohesion <= 0 {
+			t.Errorf("Unexpected untagged count %d or cohesion %v", cluster.Untagged(), cluster.Cohesion)
+		}
+	}
+
+	// The clustering is deterministic
+	again, _ := b.Clusters(context.Background(), ClusterOptions{K: 2})
+	if again[0].Notes[0] != clusters[0].Notes[0] {
+		t.Error("Expected the same clusters on a second run")
+	}
+
+	if _, err := b.Clusters(context.Background(), ClusterOptions{K: 10}); err == nil {
+		t.Error("Expected an error for more clusters than notes")
+	}
+}
+
+func TestClustersNamed(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "1: **Database tuning**\nGroup 2 - Kubernetes operations\n7: Out of range")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+	addClusterNotes(t, b)
+
+	clusters, err := b.Clusters(context.Background(), ClusterOptions{K: 2, Name: true})
+	if err != nil {
+		t.Fatalf("Clusters failed: %v", err)
+	}
+	if clusters[0].Name != "Database tuning" || clusters[1].Name != "Kubernetes operations" {
+		t.Errorf("Unexpected names %q and %q", clusters[0].Name, clusters[1].Name)
+	}
+	if len(last.Messages) != 2 || last.Messages[0].Content != clusterNamePrompt {
+		t.Errorf("Unexpected request %+v", last.Messages)
+	}
+}
+
+func TestAutoClusterCount(t *testing.T) {
+	tests := []struct {
+		notes int
+		want  int
+	}{
+		{2, 2},
+		{50, 5},
+		{200, 10},
+		{5000, maxAutoClusters},
+	}
+
+	for _, tt := range tests {
+		if got := autoClusterCount(tt.notes); got != tt.want {
+			t.Errorf("autoClusterCount(%d) = %d, want %d", tt.notes, got, tt.want)
+		}
+	}
+}
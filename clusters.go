@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var clustersCmd = &cobra.Command{
+	Use:   "clusters",
+	Short: "Discover the topics in your notes",
+	Long: `Group notes with similar embeddings using k-means and show what each
+group is about: its most characteristic terms, its size, the tags it
+uses and the notes closest to its centre. A cluster with many untagged
+notes is a theme you may want to tag (see brain retag).
+
+When a chat model is configured it also names each cluster; --no-llm
+skips that.
+
+Examples:
+  brain clusters
+  brain clusters -k 8
+  brain clusters --notes 5 --no-llm`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, _ := cmd.Flags().GetInt("k")
+		shown, _ := cmd.Flags().GetInt("notes")
+		noLLM, _ := cmd.Flags().GetBool("no-llm")
+		if k < 0 {
+			return fmt.Errorf("-k must not be negative")
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		clusters, err := b.Clusters(cmd.Context(), brain.ClusterOptions{K: k, Name: !noLLM})
+		if clusters == nil {
+			return err
+		}
+		if err != nil {
+			fmt.Printf("⚠ %v\n\n", explainError(err))
+		}
+
+		total := 0
+		for _, c := range clusters {
+			total += len(c.Notes)
+		}
+		fmt.Printf("🔍 %d clusters from %d notes\n", len(clusters), total)
+
+		for i, c := range clusters {
+			fmt.Printf("\n%d. %s", i+1, strings.Join(c.Terms, ", "))
+			if c.Name != "" {
+				fmt.Printf("  \"%s\"", c.Name)
+			}
+			fmt.Printf("  (%d notes", len(c.Notes))
+			if untagged := c.Untagged(); untagged > 0 {
+				fmt.Printf(", %d untagged", untagged)
+			}
+			fmt.Printf(", cohesion %.2f)\n", c.Cohesion)
+
+			if tags := topTags(c.Notes, 5); len(tags) > 0 {
+				fmt.Printf("   tags: %s\n", strings.Join(tags, ", "))
+			}
+			for j, note := range c.Notes {
+				if j == shown {
+					break
+				}
+				fmt.Printf("   - [%s] %s  %s\n", note.Timestamp.Format("2006-01-02"), brain.ShortID(note.ID), strings.TrimRight(fit(brain.FirstLine(note.Content), 70), " "))
+			}
+		}
+		return nil
+	},
+}
+
+// topTags returns the tags used most in notes, with their counts
+func topTags(notes []*brain.Note, n int) []string {
+	counts := make(map[string]int)
+	for _, note := range notes {
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > n {
+		tags = tags[:n]
+	}
+
+	for i, tag := range tags {
+		tags[i] = fmt.Sprintf("%s (%d)", tag, counts[tag])
+	}
+	return tags
+}
+
+func init() {
+	rootCmd.AddCommand(clustersCmd)
+	clustersCmd.Flags().IntP("k", "k", 0, "Number of clusters (0 picks one from the number of notes)")
+	clustersCmd.Flags().Int("notes", 3, "Representative notes to show per cluster")
+	clustersCmd.Flags().Bool("no-llm", false, "Don't name the clusters with the chat model")
+}
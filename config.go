@@ -0,0 +1,135 @@
+package brain
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPath overrides the location of the config file.
+// Empty means config.yaml in the data directory.
+var ConfigPath string
+
+// Config holds user settings loaded from config.yaml
+type Config struct {
+	// Embedder names an entry in Embedders or a registered embedder type.
+	// Empty means OpenAI when OPENAI_API_KEY is set, local otherwise.
+	Embedder    string                  `yaml:"embedder"`
+	Embedders   map[string]EmbedderSpec `yaml:"embedders"`
+	VectorStore VectorStoreOptions      `yaml:"vector_store"`
+	Search      SearchConfig            `yaml:"search"`
+	Ranking     RankingOptions          `yaml:"ranking"`
+	LLM         LLMConfig               `yaml:"llm"`
+	Usage       UsageConfig             `yaml:"usage"`
+}
+
+// SearchConfig holds defaults for search, ask and context
+type SearchConfig struct {
+	// MinScore drops results scoring below it; --min-score overrides it
+	MinScore float64 `yaml:"min_score"`
+}
+
+// LLMConfig selects the chat model used to answer questions
+type LLMConfig struct {
+	// BaseURL of an OpenAI-compatible API, e.g. http://localhost:11434/v1
+	// for Ollama. Servers other than OpenAI may not need an API key.
+	BaseURL   string `yaml:"base_url"`
+	Model     string `yaml:"model"`
+	APIKeyEnv string `yaml:"api_key_env"` // environment variable holding the API key
+	// ContextTokens is roughly how many tokens of notes go into a prompt
+	ContextTokens int `yaml:"context_tokens"`
+	// Grounded makes answers cite a note for every statement; --grounded
+	// turns it on for one command
+	Grounded bool `yaml:"grounded"`
+	// GroundedMinScore is the score a note needs to be used in grounded
+	// mode. Below it there is no answer rather than a guess.
+	GroundedMinScore float64 `yaml:"grounded_min_score"`
+}
+
+// DefaultConfig returns the settings used when there's no config file
+func DefaultConfig() *Config {
+	return &Config{
+		VectorStore: VectorStoreOptions{
+			Precision: PrecisionFloat32,
+		},
+		Search: SearchConfig{
+			MinScore: 0.1,
+		},
+		Ranking: DefaultRankingOptions(),
+		LLM: LLMConfig{
+			BaseURL:          openAIBaseURL,
+			Model:            openAIChatModel,
+			APIKeyEnv:        "OPENAI_API_KEY",
+			ContextTokens:    3000,
+			GroundedMinScore: 0.3,
+		},
+	}
+}
+
+// LoadConfig reads the config file at path on top of the defaults.
+// A missing file is not an error.
+func LoadConfig(path string) (*Config, error) {
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	switch c.VectorStore.Precision {
+	case PrecisionFloat32, PrecisionFloat16, PrecisionInt8:
+	default:
+		return fmt.Errorf("vector_store.precision must be float32, float16 or int8, got %q", c.VectorStore.Precision)
+	}
+	for name, spec := range c.Embedders {
+		if spec.Type == "" {
+			return fmt.Errorf("embedders.%s needs a type", name)
+		}
+		if spec.Type == "exec" && spec.Command == "" {
+			return fmt.Errorf("embedders.%s needs a command", name)
+		}
+	}
+	if c.VectorStore.Rerank < 0 {
+		return fmt.Errorf("vector_store.rerank must not be negative")
+	}
+	if c.Search.MinScore < 0 || c.Search.MinScore > 1 {
+		return fmt.Errorf("search.min_score must be between 0 and 1, got %v", c.Search.MinScore)
+	}
+	r := c.Ranking
+	if r.RecencyWeight < 0 || r.UsageWeight < 0 || r.PinnedBoost < 0 {
+		return fmt.Errorf("ranking weights must not be negative")
+	}
+	if r.RecencyWeight > 0 && r.RecencyHalfLife <= 0 {
+		return fmt.Errorf("ranking.recency_half_life must be a positive number of days")
+	}
+	if c.LLM.ContextTokens < minNoteTokens {
+		return fmt.Errorf("llm.context_tokens must be at least %d", minNoteTokens)
+	}
+	if c.LLM.GroundedMinScore < 0 || c.LLM.GroundedMinScore > 1 {
+		return fmt.Errorf("llm.grounded_min_score must be between 0 and 1, got %v", c.LLM.GroundedMinScore)
+	}
+	if c.Usage.MonthlyBudget < 0 {
+		return fmt.Errorf("usage.monthly_budget must not be negative")
+	}
+	for model, price := range c.Usage.Prices {
+		if price.Input < 0 || price.Output < 0 {
+			return fmt.Errorf("usage.prices.%s must not be negative", model)
+		}
+	}
+	return nil
+}
@@ -15,7 +15,11 @@ var contextCmd = &cobra.Command{
 	Use:   "context",
 	Short: "Show relevant notes for your current context",
 	Long: `Analyzes your current directory, git repo, and recent files
-to surface relevant notes from your brain.`,
+to surface relevant notes from your brain.
+
+Examples:
+  brain context
+  brain context --min-score 0.3 --explain`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		b, err := brain.New()
 		if err != nil {
@@ -30,7 +34,11 @@ to surface relevant notes from your brain.`,
 		}
 		fmt.Println()
 
-		results, err := b.GetContextualNotes(context)
+		explain, _ := cmd.Flags().GetBool("explain")
+		results, err := b.GetContextualNotesWithOptions(context, brain.SearchOptions{
+			Limit:    5,
+			MinScore: minScore(cmd, b),
+		})
 		if err != nil {
 			return fmt.Errorf("failed to get contextual notes: %w", err)
 		}
@@ -44,12 +52,20 @@ to surface relevant notes from your brain.`,
 		fmt.Printf("Found %d relevant note(s) for this context:\n\n", len(results))
 		for i, result := range results {
 			fmt.Printf("%d. %s\n", i+1, result.Note.Content)
+			if result.Passage != "" {
+				fmt.Printf("   Matched passage:\n%s\n", indentPassage(result.Passage))
+			}
 			if len(result.Note.Tags) > 0 {
 				fmt.Printf("   Tags: %v\n", result.Note.Tags)
 			}
-			fmt.Printf("   Relevance: %.2f%%\n\n", result.Similarity*100)
+			fmt.Printf("   Relevance: %.2f%%\n", result.Similarity*100)
+			if explain {
+				printScoreBreakdown(result)
+			}
+			fmt.Println()
 		}
 
+		recordShown(b, results)
 		return nil
 	},
 }
@@ -108,4 +124,5 @@ func getRecentCommits() []string {
 
 func init() {
 	rootCmd.AddCommand(contextCmd)
+	addScoreFlags(contextCmd)
 }
@@ -0,0 +1,247 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GroupByTag groups a digest by each note's most common tag
+	GroupByTag = "tag"
+	// GroupByProject groups a digest by project
+	GroupByProject = "project"
+)
+
+// digestLineLength caps how much of a note a digest lists, in bytes
+const digestLineLength = 200
+
+const digestSystemPrompt = `You summarise a person's or team's notes for a weekly sync.
+Write one short overview paragraph of three to five sentences covering the main themes, decisions and open questions in the notes below.
+Use only what the notes say. Don't use lists or headings, and don't cite the notes.`
+
+// DigestOptions selects the notes for a digest
+type DigestOptions struct {
+	Since   time.Time
+	Project string // only notes from this project
+	Saved   string // only notes matched by this saved search
+	GroupBy string // GroupByTag (the default) or GroupByProject
+
+	// Overview asks the chat model for an overview paragraph
+	Overview bool
+}
+
+// Digest is a summary of the notes added in a window of time
+type Digest struct {
+	Since   time.Time
+	Until   time.Time
+	Project string
+	Saved   string
+	Notes   []*Note
+	Groups  []DigestGroup
+
+	// Overview is written by the chat model, if asked for
+	Overview string
+	Model    string
+	Usage    TokenUsage
+}
+
+// DigestGroup is the notes of a digest sharing a tag or project, oldest
+// first
+type DigestGroup struct {
+	Name  string
+	Notes []*Note
+}
+
+// Digest collects the notes added since opts.Since and groups them. The
+// overview is only written when asked for and a chat model is configured;
+// if that fails the digest is returned along with the error.
+func (b *Brain) Digest(ctx context.Context, opts DigestOptions) (*Digest, error) {
+	if opts.GroupBy == "" {
+		opts.GroupBy = GroupByTag
+	}
+	if opts.GroupBy != GroupByTag && opts.GroupBy != GroupByProject {
+		return nil, fmt.Errorf("unknown grouping %q (use %s or %s)", opts.GroupBy, GroupByTag, GroupByProject)
+	}
+
+	notes, _, err := b.ListNotesWithOptions(ListOptions{Saved: opts.Saved})
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{Since: opts.Since, Until: time.Now(), Project: opts.Project, Saved: opts.Saved}
+	for i := len(notes) - 1; i >= 0; i-- {
+		note := notes[i]
+		if note.Timestamp.Before(opts.Since) {
+			continue
+		}
+		if opts.Project != "" && !strings.EqualFold(note.Project, opts.Project) {
+			continue
+		}
+		digest.Notes = append(digest.Notes, note)
+	}
+
+	if opts.GroupBy == GroupByProject {
+		digest.Groups = groupNotes(digest.Notes, "No project", func(note *Note) string {
+			return note.Project
+		})
+	} else {
+		counts := make(map[string]int)
+		for _, note := range digest.Notes {
+			for _, tag := range note.Tags {
+				counts[tag]++
+			}
+		}
+		digest.Groups = groupNotes(digest.Notes, "Untagged", func(note *Note) string {
+			return mainTag(note.Tags, counts)
+		})
+	}
+
+	if !opts.Overview || b.llm == nil || len(digest.Notes) == 0 {
+		return digest, nil
+	}
+
+	messages, err := b.prompts.digestMessages(digest, b.config.LLM.ContextTokens)
+	if err != nil {
+		return digest, err
+	}
+	completion, err := b.complete(ctx, messages, nil)
+	if err != nil {
+		return digest, fmt.Errorf("failed to write the overview: %w", err)
+	}
+	digest.Overview = strings.TrimSpace(completion.Content)
+	digest.Model = completion.Model
+	digest.Usage = completion.Usage
+	return digest, nil
+}
+
+// mainTag picks the tag a note is listed under: the one most common in
+// the digest, so notes gather in a few larger groups. It's empty for
+// untagged notes.
+func mainTag(tags []string, counts map[string]int) string {
+	best := ""
+	for _, tag := range tags {
+		if best == "" || counts[tag] > counts[best] || (counts[tag] == counts[best] && tag < best) {
+			best = tag
+		}
+	}
+	return best
+}
+
+// groupNotes groups notes by key, largest group first. Notes with an
+// empty key go in a group named other, which comes last.
+func groupNotes(notes []*Note, other string, key func(*Note) string) []DigestGroup {
+	index := make(map[string]int)
+	var groups []DigestGroup
+	var rest DigestGroup
+	for _, note := range notes {
+		name := key(note)
+		if name == "" {
+			rest.Notes = append(rest.Notes, note)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, DigestGroup{Name: name})
+		}
+		groups[i].Notes = append(groups[i].Notes, note)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Notes) != len(groups[j].Notes) {
+			return len(groups[i].Notes) > len(groups[j].Notes)
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	if len(rest.Notes) > 0 {
+		rest.Name = other
+		groups = append(groups, rest)
+	}
+	return groups
+}
+
+// digestMessages asks for an overview of the digest's notes, packed group
+// by group within the budget
+func (p *promptSet) digestMessages(digest *Digest, budget int) ([]Message, error) {
+	var results []SearchResult
+	for _, group := range digest.Groups {
+		for _, note := range group.Notes {
+			results = append(results, SearchResult{Note: note})
+		}
+	}
+	notes, _ := packNotes(results, budget)
+
+	return p.render("digest", promptData{
+		Notes:   notes,
+		Project: digest.Project,
+		Period:  digest.Period(),
+	})
+}
+
+// Period describes the digest's window, e.g. "2026-10-11 to 2026-10-18"
+func (d *Digest) Period() string {
+	return d.Since.Format("2006-01-02") + " to " + d.Until.Format("2006-01-02")
+}
+
+// Markdown renders the digest with a section per group
+func (d *Digest) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Digest: %s\n\n", d.Period())
+	summary := []string{fmt.Sprintf("%d note(s)", len(d.Notes))}
+	if d.Project != "" {
+		summary = append(summary, "project "+d.Project)
+	}
+	if d.Saved != "" {
+		summary = append(summary, "saved search "+d.Saved)
+	}
+	fmt.Fprintf(&sb, "_%s_\n\n", strings.Join(summary, " · "))
+
+	if d.Overview != "" {
+		fmt.Fprintf(&sb, "## Overview\n\n%s\n\n", d.Overview)
+	}
+
+	for _, group := range d.Groups {
+		fmt.Fprintf(&sb, "## %s (%d)\n\n", group.Name, len(group.Notes))
+		for _, note := range group.Notes {
+			line := FirstLine(note.Content)
+			if len(line) > digestLineLength {
+				line = truncateRunes(line, digestLineLength) + "…"
+			}
+			fmt.Fprintf(&sb, "- %s _(%s · `%s`)_\n", line, note.Timestamp.Format("Jan 2"), ShortID(note.ID))
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String()) + "\n"
+}
+
+// ParseSince reads the start of a time window: a duration back from now
+// such as 7d, 2w, 12h or 30m, or a date such as 2026-10-01
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		count, err := strconv.Atoi(value[:n-1])
+		if err == nil && count >= 0 {
+			days := count
+			if value[n-1] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use e.g. 7d, 2w, 12h or 2026-10-01)", value)
+}
@@ -0,0 +1,126 @@
+package brain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDigest(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	now := time.Now()
+
+	notes := []*Note{
+		{Content: "Old decision about queues", Tags: []string{"kafka"}, Timestamp: now.AddDate(0, 0, -30)},
+		{Content: "Retry payments webhooks with backoff", Tags: []string{"payments", "webhooks"}, Project: "payments", Timestamp: now.AddDate(0, 0, -3)},
+		{Content: "Refunds need an idempotency key", Tags: []string{"payments"}, Project: "payments", Timestamp: now.AddDate(0, 0, -2)},
+		{Content: "Webhook signatures use HMAC", Tags: []string{"webhooks"}, Project: "api", Timestamp: now.AddDate(0, 0, -1)},
+		{Content: "Lunch place on 5th is closed", Timestamp: now},
+	}
+	for _, note := range notes {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	digest, err := b.Digest(context.Background(), DigestOptions{Since: now.AddDate(0, 0, -7)})
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if len(digest.Notes) != 4 {
+		t.Fatalf("Expected the 4 notes from the last week, got %d", len(digest.Notes))
+	}
+
+	// Each note is listed once, under its most common tag
+	var names []string
+	for _, group := range digest.Groups {
+		names = append(names, group.Name)
+	}
+	if strings.Join(names, ",") != "payments,webhooks,Untagged" {
+		t.Errorf("Unexpected groups %v", names)
+	}
+	if first := digest.Groups[0].Notes[0]; first != notes[1] {
+		t.Errorf("Expected notes oldest first, got %q", first.Content)
+	}
+
+	markdown := digest.Markdown()
+	for _, want := range []string{"_4 note(s)_", "## payments (2)", "- Refunds need an idempotency key _(", "## webhooks (1)"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected %q in the digest:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "Overview") {
+		t.Error("Expected no overview without a chat model")
+	}
+
+	digest, err = b.Digest(context.Background(), DigestOptions{Since: now.AddDate(0, 0, -7), GroupBy: GroupByProject})
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if len(digest.Groups) != 3 || digest.Groups[0].Name != "payments" || digest.Groups[2].Name != "No project" {
+		t.Errorf("Unexpected project groups %+v", digest.Groups)
+	}
+
+	if _, err := b.Digest(context.Background(), DigestOptions{GroupBy: "month"}); err == nil {
+		t.Error("Expected an error for an unknown grouping")
+	}
+}
+
+func TestDigestOverview(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "Payments work focused on retries.")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	for _, note := range []*Note{
+		{Content: "Retry payments webhooks with backoff", Project: "payments", Timestamp: time.Now()},
+		{Content: "Webhook signatures use HMAC", Project: "api", Timestamp: time.Now()},
+	} {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	opts := DigestOptions{Since: time.Now().AddDate(0, 0, -7), Project: "Payments", Overview: true}
+	digest, err := b.Digest(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if len(digest.Notes) != 1 {
+		t.Fatalf("Expected only the payments note, got %d", len(digest.Notes))
+	}
+	if !strings.Contains(digest.Markdown(), "## Overview\n\nPayments work focused on retries.") {
+		t.Errorf("Expected the overview in the digest:\n%s", digest.Markdown())
+	}
+	if prompt := last.Messages[1].Content; !strings.Contains(prompt, "Retry payments") || strings.Contains(prompt, "HMAC") {
+		t.Errorf("Expected only the digest's notes in the prompt, got %q", prompt)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2w", want: now.AddDate(0, 0, -14)},
+		{value: "12h", want: now.Add(-12 * time.Hour)},
+		{value: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "d", wantErr: true},
+		{value: "-3d", wantErr: true},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
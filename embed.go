@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var embedCmd = &cobra.Command{
+	Use:   "embed",
+	Short: "Generate embeddings for notes that are missing them",
+	Long: `Generate embeddings for notes that were saved while the embedder
+was unavailable. Pending notes are also retried automatically on startup.
+
+Examples:
+  brain embed --pending`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pendingOnly, _ := cmd.Flags().GetBool("pending")
+		if !pendingOnly {
+			return fmt.Errorf("nothing to do, use --pending to embed queued notes")
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		pending := len(b.PendingNotes())
+		if pending == 0 {
+			fmt.Println("✓ No pending notes, everything is searchable.")
+			return nil
+		}
+
+		embedded, err := b.EmbedPending()
+		if embedded > 0 {
+			fmt.Printf("✓ Embedded %d of %d pending note(s)\n", embedded, pending)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to embed pending notes: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(embedCmd)
+	embedCmd.Flags().Bool("pending", false, "Embed notes queued while the embedder was unavailable")
+}
@@ -0,0 +1,157 @@
+package brain
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// embeddingsFile caches note embeddings between runs, so loading notes
+// doesn't call the embedding API again. An entry is only reused while the
+// note's content and the embedder that made it are unchanged.
+type embeddingsFile struct {
+	Embedder string
+	Notes    map[string]savedEmbedding // by note ID
+}
+
+// savedEmbedding is a note's embeddings as the vector store holds them
+type savedEmbedding struct {
+	Hash      string // of the content they were made from
+	Embedding savedVector
+	Chunks    []savedVector // one per passage of a long note
+}
+
+// savedVector holds one of Float32, Float16 or Int8, so quantized stores
+// write their compressed vectors rather than full precision copies
+type savedVector struct {
+	Float32 []float32
+	Float16 []uint16
+	Int8    []int8
+	Scale   float32 // per-vector scale of Int8
+}
+
+// embeddingSaver is implemented by stores whose embeddings can be saved
+type embeddingSaver interface {
+	savedEmbeddings() map[string]savedEmbedding
+}
+
+func embeddingsPath(dataDir string) string {
+	return filepath.Join(dataDir, "embeddings.gob")
+}
+
+// embedderID identifies the embedder, and model, that made an embedding
+func embedderID(embedder Embedder) string {
+	switch e := embedder.(type) {
+	case *ResilientEmbedder:
+		return embedderID(e.embedder)
+	case *OpenAIEmbedder:
+		return "openai:" + e.model
+	case *LocalEmbedder:
+		return fmt.Sprintf("local:%d", e.dimensions)
+	case *ExecEmbedder:
+		return "exec:" + strings.Join(append([]string{e.command}, e.args...), " ")
+	}
+	return fmt.Sprintf("%T", embedder)
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadEmbeddings reads the saved embeddings made by the embedder. A missing
+// or unreadable file, or one from another embedder, is treated as empty.
+func loadEmbeddings(path, embedder string) map[string]savedEmbedding {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var file embeddingsFile
+	if err := gob.NewDecoder(f).Decode(&file); err != nil || file.Embedder != embedder {
+		return nil
+	}
+	return file.Notes
+}
+
+// saveEmbeddings writes the embeddings held by the vector store
+func (b *Brain) saveEmbeddings() error {
+	store, ok := b.vectorStore.(embeddingSaver)
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Create(embeddingsPath(b.dataDir))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file := embeddingsFile{Embedder: embedderID(b.embedder), Notes: store.savedEmbeddings()}
+	if err := gob.NewEncoder(f).Encode(file); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	b.embeddingsChanged = false
+	return nil
+}
+
+// matches reports whether the saved embeddings still fit the note
+func (s savedEmbedding) matches(note *Note) bool {
+	if s.Hash != contentHash(note.Content) || s.Embedding.empty() {
+		return false
+	}
+	if len(s.Chunks) == 0 {
+		return true
+	}
+	return len(s.Chunks) == len(splitIntoChunks(note.Content, maxChunkChars, chunkOverlapChars))
+}
+
+func saveVector(v quantizedVector) savedVector {
+	switch q := v.(type) {
+	case *int8Vector:
+		return savedVector{Int8: q.values, Scale: q.scale}
+	case *float16Vector:
+		return savedVector{Float16: q.values}
+	}
+	return savedVector{Float32: v.decode()}
+}
+
+func (v savedVector) empty() bool {
+	return len(v.Float32) == 0 && len(v.Float16) == 0 && len(v.Int8) == 0
+}
+
+func (v savedVector) precision() Precision {
+	switch {
+	case len(v.Int8) > 0:
+		return PrecisionInt8
+	case len(v.Float16) > 0:
+		return PrecisionFloat16
+	}
+	return PrecisionFloat32
+}
+
+// vector returns the compressed vector, nil for full precision ones
+func (v savedVector) vector() quantizedVector {
+	switch v.precision() {
+	case PrecisionInt8:
+		return restoreInt8Vector(v.Int8, v.Scale)
+	case PrecisionFloat16:
+		return restoreFloat16Vector(v.Float16)
+	}
+	return nil
+}
+
+func (v savedVector) decode() []float32 {
+	if q := v.vector(); q != nil {
+		return q.decode()
+	}
+	return v.Float32
+}
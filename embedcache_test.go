@@ -0,0 +1,114 @@
+package brain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingEmbedder embeds locally and counts the calls
+type countingEmbedder struct {
+	local *LocalEmbedder
+	calls int
+}
+
+func (c *countingEmbedder) Embed(text string) ([]float32, error) {
+	c.calls++
+	return c.local.Embed(text)
+}
+
+func TestSavedEmbeddings(t *testing.T) {
+	tests := []struct {
+		name string
+		opts VectorStoreOptions
+	}{
+		{"float32", VectorStoreOptions{}},
+		{"float16", VectorStoreOptions{Precision: PrecisionFloat16}},
+		{"int8", VectorStoreOptions{Precision: PrecisionInt8}},
+		{"int8 with rerank", VectorStoreOptions{Precision: PrecisionInt8, Rerank: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embedder := &countingEmbedder{local: NewLocalEmbedder()}
+			b := newTestBrain(t, embedder)
+			b.config.VectorStore = tt.opts
+			b.vectorStore = &SimpleVectorStore{opts: tt.opts}
+
+			long := strings.Repeat("The deploy script tags the release and pushes the image. ", 60)
+			for _, content := range []string{"Rotate the signing keys every quarter", long} {
+				if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+					t.Fatalf("AddNote failed: %v", err)
+				}
+			}
+			query, _ := NewLocalEmbedder().Embed("signing keys")
+			before, _ := b.vectorStore.Search(query, 2, nil)
+
+			// Reloading reuses the saved embeddings instead of embedding again
+			embedder.calls = 0
+			if err := b.loadNotes(); err != nil {
+				t.Fatalf("loadNotes failed: %v", err)
+			}
+			if embedder.calls != 0 {
+				t.Fatalf("Expected no embedding calls on reload, got %d", embedder.calls)
+			}
+			after, _ := b.vectorStore.Search(query, 2, nil)
+			assertSameResults(t, before, after)
+
+			store := b.vectorStore.(*SimpleVectorStore)
+			if tt.opts.Precision == PrecisionInt8 && tt.opts.Rerank == 0 {
+				if len(store.notes[0].Embedding) != 0 {
+					t.Error("Expected only the int8 vectors to be kept in memory")
+				}
+				if q, ok := store.compressed[1].chunks[0].(*int8Vector); !ok || q.scale == 0 {
+					t.Errorf("Expected int8 chunks with their scale, got %#v", store.compressed[1].chunks[0])
+				}
+			}
+			if chunks := store.notes[1].Chunks; len(chunks) < 2 || chunks[0].Text == "" {
+				t.Errorf("Expected the long note's passages to be restored, got %d", len(chunks))
+			}
+
+			// An edited note is embedded again, the others aren't
+			embedder.calls = 0
+			if err := b.UpdateNote(store.notes[0], "Rotate the signing keys every month"); err != nil {
+				t.Fatalf("UpdateNote failed: %v", err)
+			}
+			if err := b.loadNotes(); err != nil {
+				t.Fatalf("loadNotes failed: %v", err)
+			}
+			if embedder.calls != 1 {
+				t.Errorf("Expected only the edited note to be embedded, got %d calls", embedder.calls)
+			}
+		})
+	}
+}
+
+func TestSavedEmbeddingsNeedSameEmbedder(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	if err := b.AddNote(&Note{Content: "Use the staging cluster for load tests", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+
+	embedder := &countingEmbedder{local: NewLocalEmbedder()}
+	b.embedder = embedder
+	if err := b.loadNotes(); err != nil {
+		t.Fatalf("loadNotes failed: %v", err)
+	}
+	if embedder.calls != 1 {
+		t.Errorf("Expected the note to be embedded again by the new embedder, got %d calls", embedder.calls)
+	}
+}
+
+func assertSameResults(t *testing.T, want, got []SearchResult) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Note.Content != want[i].Note.Content || !approxEqual(got[i].Similarity, want[i].Similarity) {
+			t.Errorf("Result %d: expected %q (%v), got %q (%v)", i,
+				want[i].Note.Content, want[i].Similarity, got[i].Note.Content, got[i].Similarity)
+		}
+	}
+}
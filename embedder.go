@@ -1,245 +1,197 @@
 package brain
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
 	"os"
-	"path/filepath"
-	"sort"
 	"strings"
 	"time"
+)
+
+// Embedder converts text into a vector representation
+type Embedder interface {
+	Embed(text string) ([]float32, error)
+}
+
+// ContextEmbedder is implemented by embedders that make network calls,
+// so callers can bound or cancel each request
+type ContextEmbedder interface {
+	Embedder
+	EmbedContext(ctx context.Context, text string) ([]float32, error)
+}
 
-	"github.com/google/uuid"
+const (
+	openAIEmbeddingsURL  = "https://api.openai.com/v1/embeddings"
+	openAIEmbeddingModel = "text-embedding-3-small"
 )
 
-type Note struct {
-	ID        string    `json:"id"`
-	Content   string    `json:"content"`
-	Tags      []string  `json:"tags"`
-	Project   string    `json:"project,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
-	Embedding []float32 `json:"-"` // Don't serialize, computed on demand
+// OpenAIEmbedder generates embeddings using the OpenAI API
+type OpenAIEmbedder struct {
+	apiKey string
+	model  string
+	url    string
+	client *http.Client
 }
 
-type SearchResult struct {
-	Note       *Note
-	Similarity float64
+type openAIEmbeddingRequest struct {
+	Input string `json:"input"`
+	Model string `json:"model"`
 }
 
-type Context struct {
-	Directory   string
-	Project     string
-	Description string
-	Keywords    []string
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Model string     `json:"model"`
+	Usage TokenUsage `json:"usage"`
 }
 
-type Brain struct {
-	dataDir    string
-	notesPath  string
-	embedder   Embedder
-	vectorStore VectorStore
+type openAIErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
 }
 
-// New creates a new Brain instance
-func New() (*Brain, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+// NewOpenAIEmbedder creates an embedder backed by OpenAI.
+// Returns an error if OPENAI_API_KEY is not set.
+func NewOpenAIEmbedder() (*OpenAIEmbedder, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY not set")
 	}
 
-	dataDir := filepath.Join(homeDir, ".brain")
-	notesPath := filepath.Join(dataDir, "notes.json")
+	return &OpenAIEmbedder{
+		apiKey: apiKey,
+		model:  openAIEmbeddingModel,
+		url:    openAIEmbeddingsURL,
+		client: &http.Client{},
+	}, nil
+}
+
+func (e *OpenAIEmbedder) Embed(text string) ([]float32, error) {
+	return e.EmbedContext(context.Background(), text)
+}
 
-	// Create data directory if it doesn't exist
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+func (e *OpenAIEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	usage := usageLogFrom(ctx)
+	if err := usage.allow(e.model); err != nil {
 		return nil, err
 	}
 
-	// Initialize embedder (using OpenAI by default, can be configured)
-	embedder, err := NewOpenAIEmbedder()
+	body, err := json.Marshal(openAIEmbeddingRequest{Input: text, Model: e.model})
 	if err != nil {
-		// Fall back to local embedder if OpenAI fails
-		embedder = NewLocalEmbedder()
+		return nil, err
 	}
 
-	// Initialize vector store
-	vectorStore, err := NewSimpleVectorStore(dataDir)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
 
-	b := &Brain{
-		dataDir:     dataDir,
-		notesPath:   notesPath,
-		embedder:    embedder,
-		vectorStore: vectorStore,
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// Load existing notes into vector store
-	if err := b.loadNotes(); err != nil {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, err
 	}
 
-	return b, nil
-}
-
-func (b *Brain) AddNote(note *Note) error {
-	// Generate ID if not set
-	if note.ID == "" {
-		note.ID = uuid.New().String()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, data)
 	}
 
-	// Generate embedding
-	embedding, err := b.embedder.Embed(note.Content)
-	if err != nil {
-		return fmt.Errorf("failed to generate embedding: %w", err)
+	var result openAIEmbeddingResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid embedding response: %w", err)
 	}
-	note.Embedding = embedding
-
-	// Add to vector store
-	if err := b.vectorStore.Add(note); err != nil {
-		return err
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("embedding response contained no data")
 	}
 
-	// Save to disk
-	return b.saveNotes()
-}
-
-func (b *Brain) Search(query string, limit int, tags []string) ([]SearchResult, error) {
-	// Generate embedding for query
-	embedding, err := b.embedder.Embed(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	record := UsageRecord{Kind: "embedding", Model: result.Model, PromptTokens: result.Usage.PromptTokens}
+	if record.Model == "" {
+		record.Model = e.model
+	}
+	if record.PromptTokens == 0 {
+		record.PromptTokens, record.Estimated = estimateTokens(text), true
 	}
+	usage.record(record)
 
-	// Search vector store
-	return b.vectorStore.Search(embedding, limit, tags)
+	return result.Data[0].Embedding, nil
 }
 
-func (b *Brain) GetContextualNotes(ctx interface{}) ([]SearchResult, error) {
-	// Type assert to get our Context struct
-	context, ok := ctx.(Context)
-	if !ok {
-		// Fallback if wrong type
-		return b.Search("context", 5, nil)
-	}
-
-	// Build a query from context information
-	queryParts := []string{context.Project, context.Description}
-	queryParts = append(queryParts, context.Keywords...)
-	
-	// Filter out empty strings
-	var validParts []string
-	for _, part := range queryParts {
-		if part != "" {
-			validParts = append(validParts, part)
-		}
+// newAPIError builds a typed error from a non-200 provider response
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 	}
-	
-	if len(validParts) == 0 {
-		return []SearchResult{}, nil
+
+	var errResp openAIErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Message = errResp.Error.Message
 	}
-	
-	// Join all parts into a single query string
-	query := strings.Join(validParts, " ")
-	
-	// Search with the constructed query
-	results, err := b.Search(query, 5, nil)
-	if err != nil {
-		return nil, err
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
 	}
-	
-	// If we have a project name, boost results that match it
-	if context.Project != "" {
-		for i := range results {
-			if results[i].Note.Project == context.Project {
-				results[i].Similarity *= 1.2 // 20% boost for same project
-				if results[i].Similarity > 1.0 {
-					results[i].Similarity = 1.0 // Cap at 1.0
-				}
-			}
-		}
-		
-		// Re-sort after boosting
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].Similarity > results[j].Similarity
-		})
-	}
-	
-	return results, nil
+
+	return apiErr
 }
 
-func (b *Brain) ListNotes(tags []string) ([]*Note, error) {
-	allNotes := b.vectorStore.GetAllNotes()
-	
-	if len(tags) == 0 {
-		return allNotes, nil
-	}
-	
-	// Filter by tags (use the hasAnyTag function from vectorstore)
-	var filtered []*Note
-	for _, note := range allNotes {
-		for _, filterTag := range tags {
-			hasTag := false
-			for _, noteTag := range note.Tags {
-				if noteTag == filterTag {
-					hasTag = true
-					break
-				}
-			}
-			if hasTag {
-				filtered = append(filtered, note)
-				break
-			}
-		}
-	}
-	
-	return filtered, nil
+// LocalEmbedder is a simple offline embedder based on character hashing.
+// It's far less accurate than a real model but needs no network access.
+type LocalEmbedder struct {
+	dimensions int
 }
 
-func (b *Brain) loadNotes() error {
-	// Check if notes file exists
-	if _, err := os.Stat(b.notesPath); os.IsNotExist(err) {
-		return nil // No notes yet, that's fine
-	}
+func NewLocalEmbedder() *LocalEmbedder {
+	return &LocalEmbedder{dimensions: 384}
+}
 
-	data, err := os.ReadFile(b.notesPath)
-	if err != nil {
-		return err
-	}
+func (e *LocalEmbedder) Embed(text string) ([]float32, error) {
+	embedding := make([]float32, e.dimensions)
 
-	var notes []*Note
-	if err := json.Unmarshal(data, &notes); err != nil {
-		return err
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		// Hash the whole word plus its character trigrams so that
+		// related word forms land in overlapping buckets
+		e.addFeature(embedding, word, 1.0)
+		padded := "^" + word + "$"
+		for i := 0; i+3 <= len(padded); i++ {
+			e.addFeature(embedding, padded[i:i+3], 0.5)
+		}
 	}
 
-	// Clear the vector store first to avoid duplicates
-	b.vectorStore = &SimpleVectorStore{notes: make([]*Note, 0)}
-
-	// Load each note into vector store
-	for _, note := range notes {
-		// Generate embedding if not present
-		if len(note.Embedding) == 0 {
-			embedding, err := b.embedder.Embed(note.Content)
-			if err != nil {
-				continue // Skip notes we can't embed
-			}
-			note.Embedding = embedding
+	// L2 normalize
+	var sum float32
+	for _, v := range embedding {
+		sum += v * v
+	}
+	if sum > 0 {
+		norm := float32(1.0) / float32(math.Sqrt(float64(sum)))
+		for i := range embedding {
+			embedding[i] *= norm
 		}
-		
-		b.vectorStore.Add(note)
 	}
 
-	return nil
+	return embedding, nil
 }
 
-func (b *Brain) saveNotes() error {
-	notes := b.vectorStore.GetAllNotes()
-	
-	data, err := json.MarshalIndent(notes, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(b.notesPath, data, 0644)
+func (e *LocalEmbedder) addFeature(embedding []float32, feature string, weight float32) {
+	h := fnv.New32a()
+	h.Write([]byte(feature))
+	embedding[h.Sum32()%uint32(e.dimensions)] += weight
 }
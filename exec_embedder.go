@@ -0,0 +1,190 @@
+package brain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// execTimeout bounds a single request to an external embedder
+const execTimeout = 60 * time.Second
+
+// ExecEmbedder runs a user-supplied command and talks to it over a
+// JSON-lines protocol on stdin/stdout. Each request is one line:
+//
+//	{"texts": ["first text", "second text"]}
+//
+// and the command answers with one line per request:
+//
+//	{"embeddings": [[0.1, 0.2, ...], [0.3, 0.4, ...]]}
+//
+// or {"error": "message"} on failure. The process is started on first use
+// and kept alive for the rest of the brain invocation; it should exit when
+// its stdin is closed. Anything it writes to stderr is passed through.
+type ExecEmbedder struct {
+	command string
+	args    []string
+	env     map[string]string
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan execResponse
+	done      chan struct{}
+}
+
+type execRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type execResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+	Error      string      `json:"error,omitempty"`
+	err        error       // set when the process died or sent garbage
+}
+
+func NewExecEmbedder(command string, args []string, env map[string]string) (*ExecEmbedder, error) {
+	if command == "" {
+		return nil, fmt.Errorf("exec embedder needs a command")
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, err
+	}
+
+	return &ExecEmbedder{command: command, args: args, env: env}, nil
+}
+
+func (e *ExecEmbedder) Embed(text string) ([]float32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	return e.EmbedContext(ctx, text)
+}
+
+func (e *ExecEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cmd == nil {
+		if err := e.start(); err != nil {
+			return nil, fmt.Errorf("failed to start %s: %w", e.command, err)
+		}
+	}
+
+	line, err := json.Marshal(execRequest{Texts: []string{text}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.stdin.Write(append(line, '\n')); err != nil {
+		e.stop()
+		return nil, fmt.Errorf("failed to write to %s: %w", e.command, err)
+	}
+
+	select {
+	case resp := <-e.responses:
+		switch {
+		case resp.err != nil:
+			e.stop()
+			return nil, resp.err
+		case resp.Error != "":
+			return nil, fmt.Errorf("%s: %s", e.command, resp.Error)
+		case len(resp.Embeddings) != 1:
+			return nil, fmt.Errorf("%s returned %d embeddings for 1 text", e.command, len(resp.Embeddings))
+		}
+		return resp.Embeddings[0], nil
+	case <-ctx.Done():
+		// The response may still arrive and would be read by the next
+		// request, so restart the process rather than reuse it
+		e.stop()
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the external process
+func (e *ExecEmbedder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stop()
+	return nil
+}
+
+func (e *ExecEmbedder) start() error {
+	cmd := exec.Command(e.command, e.args...)
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for k, v := range e.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	responses := make(chan execResponse)
+	done := make(chan struct{})
+	go readExecResponses(e.command, stdout, responses, done)
+
+	e.cmd, e.stdin, e.responses, e.done = cmd, stdin, responses, done
+	return nil
+}
+
+func (e *ExecEmbedder) stop() {
+	if e.cmd == nil {
+		return
+	}
+
+	close(e.done)
+	e.stdin.Close()
+	e.cmd.Process.Kill()
+	e.cmd.Wait()
+	e.cmd, e.stdin, e.responses, e.done = nil, nil, nil, nil
+}
+
+// readExecResponses decodes one response per line until the process exits
+// or done is closed
+func readExecResponses(command string, stdout io.Reader, responses chan<- execResponse, done <-chan struct{}) {
+	send := func(resp execResponse) bool {
+		select {
+		case responses <- resp:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	// Large batches of embeddings can exceed the default 64KB line limit
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		var resp execResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			resp.err = fmt.Errorf("invalid response from %s: %w", command, err)
+		}
+		if !send(resp) {
+			return
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = errors.New("process exited")
+	}
+	send(execResponse{err: fmt.Errorf("%s: %w", command, err)})
+}
@@ -0,0 +1,108 @@
+package brain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestExecEmbedderHelperProcess isn't a real test; it's the external
+// embedder spawned by the tests below
+func TestExecEmbedderHelperProcess(t *testing.T) {
+	if os.Getenv("BRAIN_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req execRequest
+		json.Unmarshal(scanner.Bytes(), &req)
+
+		resp := execResponse{}
+		for _, text := range req.Texts {
+			if text == "fail" {
+				resp = execResponse{Error: "cannot embed"}
+				break
+			}
+			// Report our pid so tests can check the process is reused
+			resp.Embeddings = append(resp.Embeddings, []float32{float32(len(text)), float32(os.Getpid())})
+		}
+		line, _ := json.Marshal(resp)
+		fmt.Println(string(line))
+	}
+	os.Exit(0)
+}
+
+func newHelperExecEmbedder(t *testing.T) *ExecEmbedder {
+	t.Helper()
+
+	e, err := NewExecEmbedder(os.Args[0], []string{"-test.run=TestExecEmbedderHelperProcess"},
+		map[string]string{"BRAIN_WANT_HELPER_PROCESS": "1"})
+	if err != nil {
+		t.Fatalf("NewExecEmbedder failed: %v", err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+func TestExecEmbedder(t *testing.T) {
+	e := newHelperExecEmbedder(t)
+
+	first, err := e.Embed("hello")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if first[0] != 5 {
+		t.Errorf("Expected first component 5, got %v", first[0])
+	}
+
+	second, err := e.Embed("hi")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if first[1] != second[1] {
+		t.Error("Expected the process to be kept alive between calls")
+	}
+
+	_, err = e.Embed("fail")
+	if err == nil || !strings.Contains(err.Error(), "cannot embed") {
+		t.Errorf("Expected the command's error to be returned, got %v", err)
+	}
+}
+
+func TestNewEmbedderFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name:   "registered type by name",
+			config: &Config{Embedder: "local"},
+		},
+		{
+			name: "named exec embedder",
+			config: &Config{
+				Embedder:  "mine",
+				Embedders: map[string]EmbedderSpec{"mine": {Type: "exec", Command: os.Args[0]}},
+			},
+		},
+		{
+			name:    "unknown embedder",
+			config:  &Config{Embedder: "nope"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newEmbedder(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newEmbedder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package brain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSynonymNotFound is returned when removing a term that isn't in the
+// synonym dictionary
+var ErrSynonymNotFound = errors.New("synonym not found")
+
+// Synonyms maps a term to the words that mean the same thing, e.g. k8s to
+// kubernetes. It's kept in synonyms.yaml in the data directory, which the
+// user can edit by hand. Entries work both ways: a query for kubernetes
+// also finds notes about k8s.
+type Synonyms map[string][]string
+
+func synonymsPath(dataDir string) string {
+	return filepath.Join(dataDir, "synonyms.yaml")
+}
+
+// loadSynonyms reads the synonym dictionary. A missing file is not an
+// error.
+func loadSynonyms(path string) (Synonyms, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Synonyms{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw Synonyms
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid synonyms file %s: %w", path, err)
+	}
+
+	// Normalize hand-edited entries so lookups can compare lowercase words
+	synonyms := Synonyms{}
+	for term, words := range raw {
+		synonyms.add(term, words...)
+	}
+	return synonyms, nil
+}
+
+// add records words as synonyms of term, skipping duplicates
+func (s Synonyms) add(term string, words ...string) {
+	term = normalizeTerm(term)
+	if term == "" {
+		return
+	}
+	for _, word := range words {
+		word = normalizeTerm(word)
+		if word == "" || word == term || containsString(s[term], word) {
+			continue
+		}
+		s[term] = append(s[term], word)
+	}
+}
+
+// Expand returns text with the synonyms of any terms it contains added on
+// the end. Multi-word terms like "slow response" match whole words in
+// order. Words already in the text aren't added again.
+func (s Synonyms) Expand(text string) string {
+	if len(s) == 0 {
+		return text
+	}
+
+	words := queryWords(text)
+	var added []string
+	addWord := func(word string) {
+		if !containsWords(words, queryWords(word)) && !containsString(added, word) {
+			added = append(added, word)
+		}
+	}
+
+	// Sorted so the expanded text, and so the cached query embedding, is
+	// the same every time
+	terms := make([]string, 0, len(s))
+	for term := range s {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	for _, term := range terms {
+		synonyms := s[term]
+		if containsWords(words, queryWords(term)) {
+			for _, synonym := range synonyms {
+				addWord(synonym)
+			}
+			continue
+		}
+		for _, synonym := range synonyms {
+			if containsWords(words, queryWords(synonym)) {
+				addWord(term)
+				for _, other := range synonyms {
+					addWord(other)
+				}
+				break
+			}
+		}
+	}
+
+	if len(added) == 0 {
+		return text
+	}
+	return text + " " + strings.Join(added, " ")
+}
+
+// Synonyms returns the synonym dictionary
+func (b *Brain) Synonyms() Synonyms {
+	return b.synonyms
+}
+
+// AddSynonyms adds words as synonyms of term and saves the dictionary
+func (b *Brain) AddSynonyms(term string, words ...string) error {
+	if normalizeTerm(term) == "" {
+		return fmt.Errorf("synonym term can't be empty")
+	}
+	if b.synonyms == nil {
+		b.synonyms = Synonyms{}
+	}
+	b.synonyms.add(term, words...)
+	return b.saveSynonyms()
+}
+
+// RemoveSynonyms removes words from term's synonyms, or the whole entry
+// when no words are given, and saves the dictionary
+func (b *Brain) RemoveSynonyms(term string, words ...string) error {
+	term = normalizeTerm(term)
+	existing, ok := b.synonyms[term]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSynonymNotFound, term)
+	}
+
+	if len(words) == 0 {
+		delete(b.synonyms, term)
+		return b.saveSynonyms()
+	}
+
+	kept := existing[:0]
+	for _, synonym := range existing {
+		remove := false
+		for _, word := range words {
+			if normalizeTerm(word) == synonym {
+				remove = true
+			}
+		}
+		if !remove {
+			kept = append(kept, synonym)
+		}
+	}
+	if len(kept) == len(existing) {
+		return fmt.Errorf("%w: %s is not a synonym of %s", ErrSynonymNotFound, strings.Join(words, ", "), term)
+	}
+
+	if len(kept) == 0 {
+		delete(b.synonyms, term)
+	} else {
+		b.synonyms[term] = kept
+	}
+	return b.saveSynonyms()
+}
+
+func (b *Brain) saveSynonyms() error {
+	data, err := yaml.Marshal(b.synonyms)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(synonymsPath(b.dataDir), data, 0644)
+}
+
+// normalizeTerm lowercases a term and collapses its whitespace
+func normalizeTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
+
+// queryWords splits text into lowercase words for synonym matching
+func queryWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+}
+
+// containsWords reports whether phrase appears in words as a run of whole
+// words
+func containsWords(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
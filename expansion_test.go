@@ -0,0 +1,108 @@
+package brain
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSynonymsExpand(t *testing.T) {
+	synonyms := Synonyms{}
+	synonyms.add("k8s", "kubernetes")
+	synonyms.add("PG", "Postgres", "postgresql")
+	synonyms.add("latency", "slow response")
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"k8s deploys", "k8s deploys kubernetes"},
+		{"tuning pg", "tuning pg postgres postgresql"},
+		{"postgres vacuum", "postgres vacuum pg postgresql"},
+		{"why is the slow response", "why is the slow response latency"},
+		{"slow queries", "slow queries"},
+		{"k8s and kubernetes", "k8s and kubernetes"},
+		{"k8s-operator", "k8s-operator"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := synonyms.Expand(tt.text); got != tt.expected {
+			t.Errorf("Expand(%q) = %q, want %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestSynonymsImproveSearch(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	note := &Note{Content: "Kubernetes liveness probes restart stuck pods", Timestamp: time.Now()}
+	if err := b.AddNote(note); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+
+	opts := SearchOptions{Limit: 5, Mode: SearchModeKeyword}
+	if results, _ := b.SearchWithOptions("k8s", opts); len(results) != 0 {
+		t.Fatalf("Expected no match before adding the synonym, got %d", len(results))
+	}
+
+	if err := b.AddSynonyms("k8s", "kubernetes"); err != nil {
+		t.Fatalf("AddSynonyms failed: %v", err)
+	}
+	results, err := b.SearchWithOptions("k8s", opts)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Note != note {
+		t.Errorf("Expected k8s to find the kubernetes note, got %d results", len(results))
+	}
+}
+
+func TestSynonymsPersistence(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	path := synonymsPath(b.dataDir)
+
+	if err := b.AddSynonyms("pg", "postgres", "postgresql"); err != nil {
+		t.Fatalf("AddSynonyms failed: %v", err)
+	}
+	if err := b.RemoveSynonyms("pg", "postgresql"); err != nil {
+		t.Fatalf("RemoveSynonyms failed: %v", err)
+	}
+
+	loaded, err := loadSynonyms(path)
+	if err != nil {
+		t.Fatalf("loadSynonyms failed: %v", err)
+	}
+	if got := loaded["pg"]; len(got) != 1 || got[0] != "postgres" {
+		t.Errorf("Expected pg → [postgres] after reload, got %v", got)
+	}
+
+	if err := b.RemoveSynonyms("pg", "mysql"); !errors.Is(err, ErrSynonymNotFound) {
+		t.Errorf("Expected ErrSynonymNotFound for an unknown synonym, got %v", err)
+	}
+	if err := b.RemoveSynonyms("pg"); err != nil {
+		t.Fatalf("RemoveSynonyms failed: %v", err)
+	}
+	if err := b.RemoveSynonyms("pg"); !errors.Is(err, ErrSynonymNotFound) {
+		t.Errorf("Expected ErrSynonymNotFound for a removed term, got %v", err)
+	}
+
+	// Hand-edited files are normalized when loaded
+	if err := os.WriteFile(path, []byte("K8S: [Kubernetes, kubernetes]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = loadSynonyms(path)
+	if err != nil {
+		t.Fatalf("loadSynonyms failed: %v", err)
+	}
+	if got := loaded["k8s"]; len(got) != 1 || got[0] != "kubernetes" {
+		t.Errorf("Expected k8s → [kubernetes], got %v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("k8s: {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSynonyms(path); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export notes as JSON or Markdown",
+	Long: `Export notes, most recent first, to stdout or a file.
+
+Examples:
+  brain export > notes.json
+  brain export --format markdown --tags go -o go-notes.md
+  brain export --saved payments-incidents --format markdown`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		saved, _ := cmd.Flags().GetString("saved")
+
+		if format != "json" && format != "markdown" {
+			return fmt.Errorf("unknown format %q (use json or markdown)", format)
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		notes, _, err := b.ListNotesWithOptions(brain.ListOptions{
+			Filter: brain.TagFilter{Any: tags},
+			Saved:  saved,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if notes == nil {
+				notes = []*brain.Note{}
+			}
+			err = enc.Encode(notes)
+		} else {
+			err = writeMarkdown(w, notes)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export notes: %w", err)
+		}
+
+		if output != "" {
+			fmt.Printf("✓ Exported %d note(s) to %s\n", len(notes), output)
+		}
+		return nil
+	},
+}
+
+// writeMarkdown writes one section per note
+func writeMarkdown(w io.Writer, notes []*brain.Note) error {
+	for _, note := range notes {
+		var meta []string
+		if len(note.Tags) > 0 {
+			meta = append(meta, "Tags: "+strings.Join(note.Tags, ", "))
+		}
+		if note.Project != "" {
+			meta = append(meta, "Project: "+note.Project)
+		}
+		meta = append(meta, "ID: "+note.ID)
+
+		_, err := fmt.Fprintf(w, "## %s\n\n%s\n\n_%s_\n\n",
+			note.Timestamp.Format("2006-01-02 15:04"), note.Content, strings.Join(meta, " · "))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("format", "f", "json", "Output format: json or markdown")
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+	exportCmd.Flags().StringSliceP("tags", "t", []string{}, "Only notes with any of these tags")
+	exportCmd.Flags().String("saved", "", "Only notes matched by this saved search")
+}
@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+const (
+	// findDebounce is how long typing has to pause before searching
+	findDebounce = 200 * time.Millisecond
+
+	// findLimit is the most results kept for browsing
+	findLimit = 100
+)
+
+var findCmd = &cobra.Command{
+	Use:   "find [query]",
+	Short: "Search interactively in a full-screen view",
+	Long: `Search your notes as you type, with a preview of the selected note.
+
+Keys:
+  type            edit the query (same syntax as brain search)
+  ↑ ↓ PgUp PgDn   move through the results
+  Enter           print the selected note and exit
+  Ctrl-E          edit the note in $EDITOR
+  Ctrl-D          delete the note (asks first)
+  Ctrl-Y          copy the note to the clipboard
+  Ctrl-O          show notes linked with @id mentions (Esc to go back)
+  Esc, Ctrl-C     quit
+
+Examples:
+  brain find
+  brain find "tag:go retries"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		term, err := openTerminal()
+		if err != nil {
+			return err
+		}
+
+		// The terminal is restored before the opened note is printed, and
+		// also if the finder panics
+		f := &finder{b: b, term: term, query: []rune(strings.Join(args, " "))}
+		opened := func() *brain.Note {
+			defer term.Close()
+			return f.run()
+		}()
+
+		if opened != nil {
+			printNote(1, opened)
+			if err := b.RecordAccess(opened); err != nil {
+				fmt.Printf("⚠ Could not record note usage: %v\n", err)
+			}
+		}
+		return nil
+	},
+}
+
+// finder is the state of the brain find screen
+type finder struct {
+	b    *brain.Brain
+	term *terminal
+
+	query    []rune
+	results  []brain.SearchResult
+	selected int
+	scroll   int
+
+	linksOf       *brain.Note // showing notes linked to this one instead of search results
+	confirmDelete bool
+	status        string // one-off message shown in place of the key help
+}
+
+// run shows the screen until the user quits and returns the note they
+// opened, if any
+func (f *finder) run() *brain.Note {
+	debounce := time.NewTimer(0) // search straight away for the initial query
+
+	for {
+		f.render()
+
+		select {
+		case k, ok := <-f.term.keys:
+			if !ok {
+				return nil
+			}
+			f.status = ""
+
+			queryChanged, done := f.handleKey(k)
+			if done {
+				if k.code == keyEnter {
+					return f.current()
+				}
+				return nil
+			}
+			if queryChanged {
+				if !debounce.Stop() {
+					select {
+					case <-debounce.C:
+					default:
+					}
+				}
+				debounce.Reset(findDebounce)
+			}
+		case <-debounce.C:
+			f.search()
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether the query changed and
+// whether the screen should close
+func (f *finder) handleKey(k key) (queryChanged, done bool) {
+	if f.confirmDelete {
+		f.confirmDelete = false
+		if k.code == keyRune && unicode.ToLower(k.r) == 'y' {
+			f.deleteCurrent()
+		} else {
+			f.status = "Not deleted"
+		}
+		return false, false
+	}
+
+	switch k.code {
+	case keyRune:
+		f.query = append(f.query, k.r)
+		f.linksOf = nil
+		return true, false
+	case keyBackspace:
+		if len(f.query) > 0 {
+			f.query = f.query[:len(f.query)-1]
+		}
+		f.linksOf = nil
+		return true, false
+	case keyEsc:
+		if f.linksOf != nil {
+			f.linksOf = nil
+			return true, false
+		}
+		return false, true
+	case keyEnter:
+		return false, f.current() != nil
+	case keyUp:
+		f.move(-1)
+	case keyDown:
+		f.move(1)
+	case keyPageUp:
+		f.move(-f.listHeight())
+	case keyPageDown:
+		f.move(f.listHeight())
+	case keyHome:
+		f.move(-len(f.results))
+	case keyEnd:
+		f.move(len(f.results))
+	case keyCtrl:
+		switch k.r {
+		case 'c', 'q':
+			return false, true
+		case 'p':
+			f.move(-1)
+		case 'n':
+			f.move(1)
+		case 'u':
+			f.query = nil
+			f.linksOf = nil
+			return true, false
+		case 'e':
+			f.editCurrent()
+		case 'd':
+			if f.current() != nil {
+				f.confirmDelete = true
+			}
+		case 'y':
+			f.copyCurrent()
+		case 'o':
+			f.showLinks()
+		}
+	}
+	return false, false
+}
+
+// search refreshes the results for the current query. An empty query
+// lists the most recent notes.
+func (f *finder) search() {
+	if f.linksOf != nil {
+		return
+	}
+
+	query := strings.TrimSpace(string(f.query))
+	if query == "" {
+		notes, _, _ := f.b.ListNotesWithOptions(brain.ListOptions{Limit: findLimit})
+		f.setResults(asResults(notes))
+		return
+	}
+
+	results, err := f.b.SearchWithOptions(query, brain.SearchOptions{
+		Limit:    findLimit,
+		Mode:     brain.SearchModeHybrid,
+		MinScore: f.b.Config().Search.MinScore,
+	})
+	if err != nil {
+		// Usually a half-typed filter; keep the last results meanwhile
+		f.status = err.Error()
+		return
+	}
+	f.setResults(results)
+}
+
+func (f *finder) setResults(results []brain.SearchResult) {
+	f.results = results
+	f.selected, f.scroll = 0, 0
+}
+
+// asResults wraps notes that weren't scored against a query
+func asResults(notes []*brain.Note) []brain.SearchResult {
+	results := make([]brain.SearchResult, len(notes))
+	for i, note := range notes {
+		results[i] = brain.SearchResult{Note: note}
+	}
+	return results
+}
+
+func (f *finder) current() *brain.Note {
+	if f.selected < len(f.results) {
+		return f.results[f.selected].Note
+	}
+	return nil
+}
+
+func (f *finder) move(delta int) {
+	f.selected += delta
+	if f.selected >= len(f.results) {
+		f.selected = len(f.results) - 1
+	}
+	if f.selected < 0 {
+		f.selected = 0
+	}
+}
+
+func (f *finder) editCurrent() {
+	note := f.current()
+	if note == nil {
+		return
+	}
+
+	f.term.suspend()
+	content, err := editText(note.Content)
+	if resumeErr := f.term.resume(); resumeErr != nil && err == nil {
+		err = resumeErr
+	}
+
+	switch {
+	case err != nil:
+		f.status = "Edit failed: " + err.Error()
+	case content == "" || content == note.Content:
+		f.status = "No changes"
+	default:
+		err := f.b.UpdateNote(note, content)
+		switch {
+		case errors.Is(err, brain.ErrEmbeddingPending):
+			f.status = "Saved; embedding pending, run brain embed --pending"
+		case err != nil:
+			f.status = "Save failed: " + err.Error()
+		default:
+			f.status = "Saved"
+		}
+		if f.linksOf == nil {
+			f.search()
+		}
+	}
+}
+
+// editText opens text in $EDITOR (vi by default) and returns the result
+func editText(text string) (string, error) {
+	file, err := os.CreateTemp("", "brain-note-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	file.Close()
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(edited)), nil
+}
+
+func (f *finder) deleteCurrent() {
+	note := f.current()
+	if note == nil {
+		return
+	}
+
+	if err := f.b.DeleteNote(note); err != nil {
+		f.status = "Delete failed: " + err.Error()
+		return
+	}
+
+	f.results = append(f.results[:f.selected], f.results[f.selected+1:]...)
+	f.move(0)
+	f.status = "Deleted"
+}
+
+func (f *finder) copyCurrent() {
+	note := f.current()
+	if note == nil {
+		return
+	}
+
+	if copyToClipboard(note.Content) {
+		f.status = "Copied to clipboard"
+		return
+	}
+
+	// Fall back to asking the terminal itself (OSC 52), which also works
+	// over SSH in most terminal emulators
+	fmt.Printf("\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(note.Content)))
+	f.status = "Sent to the terminal clipboard"
+}
+
+// copyToClipboard tries the usual clipboard commands for the platform
+func copyToClipboard(text string) bool {
+	var candidates [][]string
+	switch {
+	case runtime.GOOS == "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		candidates = [][]string{{"wl-copy"}}
+	case os.Getenv("DISPLAY") != "":
+		candidates = [][]string{{"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+
+	for _, args := range candidates {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *finder) showLinks() {
+	note := f.current()
+	if note == nil {
+		return
+	}
+
+	linked := f.b.LinkedNotes(note)
+	if len(linked) == 0 {
+		f.status = "No linked notes; link one by writing @<id> in a note"
+		return
+	}
+
+	f.linksOf = note
+	f.setResults(asResults(linked))
+}
+
+// listHeight is how many result rows fit on screen
+func (f *finder) listHeight() int {
+	rows, _ := f.term.size()
+	if h := rows - 4; h > 1 {
+		return h
+	}
+	return 1
+}
+
+// render redraws the whole screen: query line, result count, results on
+// the left with a preview of the selected note on the right, and a key
+// help or status line at the bottom
+func (f *finder) render() {
+	rows, cols := f.term.size()
+	height := f.listHeight()
+	listWidth := cols * 2 / 5
+	previewWidth := cols - listWidth - 3
+
+	if f.selected < f.scroll {
+		f.scroll = f.selected
+	}
+	if f.selected >= f.scroll+height {
+		f.scroll = f.selected - height + 1
+	}
+
+	var out bytes.Buffer
+	out.WriteString(cursorHome)
+	line := func(s string) {
+		out.WriteString(s + clearLine + "\r\n")
+	}
+
+	if f.linksOf != nil {
+		line(styleBold + fit("Linked to: "+brain.FirstLine(f.linksOf.Content), cols) + styleReset)
+	} else {
+		line(styleBold + "> " + styleReset + fit(string(f.query), cols-3) + styleReverse + " " + styleReset)
+	}
+	line(styleDim + fit(fmt.Sprintf("  %d note(s)", len(f.results)), cols) + styleReset)
+	line(styleDim + strings.Repeat("─", listWidth) + "─┬─" + strings.Repeat("─", max(previewWidth, 0)) + styleReset)
+
+	preview := f.previewLines(previewWidth)
+	for i := 0; i < height; i++ {
+		left := strings.Repeat(" ", listWidth)
+		if n := f.scroll + i; n < len(f.results) {
+			r := f.results[n]
+			label := brain.FirstLine(r.Note.Content)
+			if r.Similarity > 0 {
+				label = fmt.Sprintf("%3.0f%% %s", r.Similarity*100, label)
+			}
+			left = fit(" "+label, listWidth)
+			if n == f.selected {
+				left = styleReverse + left + styleReset
+			}
+		}
+
+		right := ""
+		if i < len(preview) {
+			right = preview[i]
+		}
+		line(left + styleDim + " │ " + styleReset + right)
+	}
+
+	bottom := "↑↓ move  Enter open  ^E edit  ^D delete  ^Y copy  ^O links  Esc quit"
+	switch {
+	case f.confirmDelete:
+		bottom = styleBold + "Delete this note? (y/n)" + styleReset
+	case f.status != "":
+		bottom = styleBold + fit(f.status, cols) + styleReset
+	default:
+		bottom = styleDim + fit(bottom, cols) + styleReset
+	}
+	out.WriteString(bottom + clearLine)
+
+	// Clear anything left below after the terminal was resized
+	for i := height + 4; i < rows; i++ {
+		out.WriteString("\r\n" + clearLine)
+	}
+
+	os.Stdout.Write(out.Bytes())
+}
+
+// previewLines lays out the selected note for the preview pane
+func (f *finder) previewLines(width int) []string {
+	note := f.current()
+	if note == nil || width <= 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(note.Content, "\n") {
+		lines = append(lines, wrap(paragraph, width)...)
+	}
+	lines = append(lines, "")
+	if len(note.Tags) > 0 {
+		lines = append(lines, fit("Tags: "+strings.Join(note.Tags, ", "), width))
+	}
+	if note.Project != "" {
+		lines = append(lines, fit("Project: "+note.Project, width))
+	}
+	lines = append(lines, fit("Added: "+note.Timestamp.Format("2006-01-02 15:04"), width))
+	if note.Pinned {
+		lines = append(lines, "Pinned")
+	}
+	lines = append(lines, styleDim+fit("ID: "+note.ID, width)+styleReset)
+	return lines
+}
+
+// fit pads or truncates s to exactly width columns, dropping control
+// characters that would upset the layout
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if n := utf8.RuneCountInString(s); n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// wrap breaks a paragraph into lines of at most width columns
+func wrap(paragraph string, width int) []string {
+	words := strings.Fields(paragraph)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, fit(current, width))
+			current = word
+		}
+	}
+	return append(lines, fit(current, width))
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+}
@@ -0,0 +1,246 @@
+package brain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SearchMode selects how notes are ranked against a query
+type SearchMode string
+
+const (
+	SearchModeSemantic SearchMode = "semantic" // embedding similarity only
+	SearchModeKeyword  SearchMode = "keyword"  // BM25 over note content only
+	SearchModeHybrid   SearchMode = "hybrid"   // weighted blend of both
+)
+
+// hybridSemanticWeight is the share of the hybrid score that comes from
+// embedding similarity; the rest comes from the normalized BM25 score
+const hybridSemanticWeight = 0.5
+
+// ParseSearchMode validates a mode given on the command line
+func ParseSearchMode(s string) (SearchMode, error) {
+	switch mode := SearchMode(s); mode {
+	case SearchModeSemantic, SearchModeKeyword, SearchModeHybrid:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown search mode %q (use hybrid, semantic or keyword)", s)
+}
+
+// SearchOptions controls SearchWithOptions
+type SearchOptions struct {
+	Limit       int
+	Offset      int        // skip this many results, for paging
+	Tags        []string   // added to the query's tag: filters
+	AllTags     []string   // notes must have every one of these tags
+	ExcludeTags []string   // added to the query's -tag: filters
+	Mode        SearchMode // empty means semantic; brain search passes hybrid by default
+	MinScore    float64    // drop results scoring below this, ignored for filter-only queries
+	Diversity   float64    // 0 ranks by score only, up to 1 favours distinct results (see diversify)
+	// PlainText ranks the query as it is, without parsing the query
+	// syntax, for natural-language questions
+	PlainText bool
+}
+
+// SearchWithOptions parses the query syntax (see Query), ranks notes by
+// its text with the chosen mode and applies its filters
+func (b *Brain) SearchWithOptions(query string, opts SearchOptions) ([]SearchResult, error) {
+	if opts.PlainText {
+		return b.searchText(query, opts)
+	}
+
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	q.Tags = append(q.Tags, opts.Tags...)
+	q.AllTags = append(q.AllTags, opts.AllTags...)
+	q.ExcludeTags = append(q.ExcludeTags, opts.ExcludeTags...)
+
+	text := q.SearchText()
+
+	var results []SearchResult
+	if text == "" {
+		// Only filters, nothing to rank by
+		results = b.filterOnlyResults()
+	} else {
+		results, err = b.rankText(text, opts.Mode)
+		if err != nil {
+			return nil, err
+		}
+		b.config.Ranking.rank(results, time.Now())
+	}
+
+	filtered := results[:0]
+	for _, r := range results {
+		if q.Matches(r.Note) {
+			filtered = append(filtered, r)
+		}
+	}
+	if text != "" {
+		filtered = dropBelow(filtered, opts.MinScore)
+		filtered = b.diversify(filtered, opts.Diversity, mmrPoolFactor*opts.Limit)
+	}
+
+	return paginate(filtered, opts.Offset, opts.Limit), nil
+}
+
+// searchText ranks notes against plain text, which isn't parsed as query
+// syntax, then applies the tag filters, score threshold, diversity and
+// paging in opts
+func (b *Brain) searchText(text string, opts SearchOptions) ([]SearchResult, error) {
+	results, err := b.rankText(text, opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+	b.config.Ranking.rank(results, time.Now())
+
+	filter := TagFilter{Any: opts.Tags, All: opts.AllTags, Exclude: opts.ExcludeTags}
+	filtered := results[:0]
+	for _, r := range results {
+		if filter.Matches(r.Note.Tags) {
+			filtered = append(filtered, r)
+		}
+	}
+	filtered = dropBelow(filtered, opts.MinScore)
+	filtered = b.diversify(filtered, opts.Diversity, mmrPoolFactor*opts.Limit)
+
+	return paginate(filtered, opts.Offset, opts.Limit), nil
+}
+
+// paginate returns the items from offset on, at most limit of them
+// (all of them when limit is 0)
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// rankText scores every note against plain text with the given mode,
+// after adding synonyms from the user's dictionary
+func (b *Brain) rankText(text string, mode SearchMode) ([]SearchResult, error) {
+	text = b.synonyms.Expand(text)
+	switch mode {
+	case SearchModeKeyword:
+		return b.keywordResults(text), nil
+	case SearchModeHybrid:
+		semantic, err := b.semanticResults(text)
+		if err != nil {
+			return nil, err
+		}
+		return fuseResults(semantic, b.keywordResults(text)), nil
+	default:
+		return b.semanticResults(text)
+	}
+}
+
+// semanticResults ranks every embedded note by similarity to the text
+func (b *Brain) semanticResults(text string) ([]SearchResult, error) {
+	// Generate embedding for query
+	if text != b.lastQuery || b.lastQueryEmbedding == nil {
+		embedding, err := b.embed(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		}
+		b.lastQuery, b.lastQueryEmbedding = text, embedding
+	}
+
+	// Search vector store
+	results, err := b.vectorStore.Search(b.lastQueryEmbedding, len(b.vectorStore.GetAllNotes()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Score = ScoreBreakdown{Mode: SearchModeSemantic, Cosine: results[i].Similarity}
+	}
+	return results, nil
+}
+
+// keywordResults ranks notes by BM25, scaled so the best match scores 1
+func (b *Brain) keywordResults(text string) []SearchResult {
+	scores := b.keywords.Scores(text)
+
+	var best float64
+	for _, score := range scores {
+		if score > best {
+			best = score
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for note, score := range scores {
+		results = append(results, SearchResult{
+			Note:       note,
+			Similarity: score / best,
+			Score:      ScoreBreakdown{Mode: SearchModeKeyword, Keyword: score / best},
+		})
+	}
+	sortResults(results)
+
+	return results
+}
+
+// filterOnlyResults returns every note, newest first, as a full match
+func (b *Brain) filterOnlyResults() []SearchResult {
+	notes := b.vectorStore.GetAllNotes()
+
+	results := make([]SearchResult, 0, len(notes))
+	for _, note := range notes {
+		results = append(results, SearchResult{Note: note, Similarity: 1})
+	}
+	sortResults(results)
+
+	return results
+}
+
+// fuseResults blends semantic and keyword scores per note. Notes found by
+// only one of them get zero for the other, so a pending note can still be
+// found by keyword.
+func fuseResults(semantic, keyword []SearchResult) []SearchResult {
+	fused := make(map[*Note]*SearchResult)
+	for _, r := range semantic {
+		r := r
+		r.Similarity *= hybridSemanticWeight
+		r.Score.Mode = SearchModeHybrid
+		fused[r.Note] = &r
+	}
+	for _, r := range keyword {
+		if existing, ok := fused[r.Note]; ok {
+			existing.Similarity += (1 - hybridSemanticWeight) * r.Similarity
+			existing.Score.Keyword = r.Score.Keyword
+		} else {
+			r.Similarity *= 1 - hybridSemanticWeight
+			r.Score.Mode = SearchModeHybrid
+			fused[r.Note] = &r
+		}
+	}
+
+	results := make([]SearchResult, 0, len(fused))
+	for _, r := range fused {
+		results = append(results, *r)
+	}
+	sortResults(results)
+
+	return results
+}
+
+// sortResults orders results by score, breaking ties by newest first so
+// the order is stable across runs
+func sortResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Similarity != results[j].Similarity {
+			return results[i].Similarity > results[j].Similarity
+		}
+		return results[i].Note.Timestamp.After(results[j].Note.Timestamp)
+	})
+}
@@ -0,0 +1,119 @@
+package brain
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, the usual defaults
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// keywordIndex is an inverted index over note content scored with BM25.
+// It complements embeddings for exact identifiers like ERR_CONN_RESET or
+// ticket numbers, which embeddings tend to blur.
+type keywordIndex struct {
+	postings map[string]map[*Note]int // term -> note -> term frequency
+	lengths  map[*Note]int
+	totalLen int
+}
+
+func newKeywordIndex() *keywordIndex {
+	return &keywordIndex{
+		postings: make(map[string]map[*Note]int),
+		lengths:  make(map[*Note]int),
+	}
+}
+
+func (idx *keywordIndex) Add(note *Note) {
+	terms := tokenize(note.Content)
+	for _, term := range terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[*Note]int)
+		}
+		idx.postings[term][note]++
+	}
+	idx.lengths[note] = len(terms)
+	idx.totalLen += len(terms)
+}
+
+// Remove drops a note from the index. The note's content must not have
+// changed since it was added.
+func (idx *keywordIndex) Remove(note *Note) {
+	if _, ok := idx.lengths[note]; !ok {
+		return
+	}
+
+	for _, term := range tokenize(note.Content) {
+		delete(idx.postings[term], note)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLen -= idx.lengths[note]
+	delete(idx.lengths, note)
+}
+
+// Scores returns the BM25 score of every note matching at least one
+// query term
+func (idx *keywordIndex) Scores(query string) map[*Note]float64 {
+	scores := make(map[*Note]float64)
+	if len(idx.lengths) == 0 {
+		return scores
+	}
+
+	n := float64(len(idx.lengths))
+	avgLen := float64(idx.totalLen) / n
+
+	seen := make(map[string]bool)
+	for _, term := range tokenize(query) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for note, tf := range postings {
+			freq := float64(tf)
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[note])/avgLen
+			scores[note] += idf * freq * (bm25K1 + 1) / (freq + bm25K1*norm)
+		}
+	}
+
+	return scores
+}
+
+// tokenize lowercases text and splits it into terms. Identifiers joined by
+// '_' or '-' are kept whole and also indexed by their parts, so both
+// "ERR_CONN_RESET" and "reset" match.
+func tokenize(text string) []string {
+	var terms []string
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+	for _, word := range words {
+		word = strings.Trim(word, "_-")
+		if word == "" {
+			continue
+		}
+		terms = append(terms, word)
+
+		if strings.ContainsAny(word, "_-") {
+			for _, part := range strings.FieldsFunc(word, func(r rune) bool { return r == '_' || r == '-' }) {
+				terms = append(terms, part)
+			}
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,72 @@
+package brain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"Redis caching", []string{"redis", "caching"}},
+		{"Got ERR_CONN_RESET again!", []string{"got", "err_conn_reset", "err", "conn", "reset", "again"}},
+		{"See PAY-1234.", []string{"see", "pay-1234", "pay", "1234"}},
+		{"-- dashes --", []string{"dashes"}},
+	}
+
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("tokenize(%q) = %v, want %v", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestKeywordIndexScores(t *testing.T) {
+	idx := newKeywordIndex()
+	exact := &Note{Content: "Client saw ERR_CONN_RESET when the proxy restarted"}
+	prose := &Note{Content: "Connections were reset by the load balancer during deploys"}
+	other := &Note{Content: "Use tabs for indentation"}
+	for _, note := range []*Note{exact, prose, other} {
+		idx.Add(note)
+	}
+
+	scores := idx.Scores("ERR_CONN_RESET")
+	if scores[exact] <= scores[prose] {
+		t.Errorf("Expected the exact identifier to score highest, got %v vs %v", scores[exact], scores[prose])
+	}
+	if _, ok := scores[other]; ok {
+		t.Error("Expected notes without any query term to be left out")
+	}
+}
+
+func TestSearchModes(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, content := range []string{
+		"Ticket PAY-4821: refunds failed after the currency migration",
+		"Payment refunds are slow when the ledger service is under load",
+		"Prefer table-driven tests in Go",
+	} {
+		if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	for _, mode := range []SearchMode{SearchModeKeyword, SearchModeHybrid} {
+		results, err := b.SearchWithOptions("PAY-4821", SearchOptions{Limit: 3, Mode: mode})
+		if err != nil {
+			t.Fatalf("%s search failed: %v", mode, err)
+		}
+		if len(results) == 0 || !strings.HasPrefix(results[0].Note.Content, "Ticket PAY-4821") {
+			t.Errorf("%s search: expected the ticket note first, got %+v", mode, results)
+		}
+	}
+
+	results, _ := b.SearchWithOptions("PAY-4821", SearchOptions{Limit: 3, Mode: SearchModeKeyword})
+	if len(results) != 1 {
+		t.Errorf("Expected keyword search to return only matching notes, got %d", len(results))
+	}
+}
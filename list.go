@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/brain-cli/internal/brain"
@@ -16,55 +15,93 @@ var listCmd = &cobra.Command{
 Examples:
   brain list
   brain list --limit 10
-  brain list --tags go,performance`,
+  brain list --limit 50 --page 3
+  brain list --tags go,performance
+  brain list --tags lang/go          # includes lang/go/concurrency etc.
+  brain list --all-tags go,http --exclude-tags draft
+  brain list --saved payments-incidents`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		limit, _ := cmd.Flags().GetInt("limit")
 		tags, _ := cmd.Flags().GetStringSlice("tags")
+		allTags, _ := cmd.Flags().GetStringSlice("all-tags")
+		excludeTags, _ := cmd.Flags().GetStringSlice("exclude-tags")
+		saved, _ := cmd.Flags().GetString("saved")
 
 		b, err := brain.New()
 		if err != nil {
 			return fmt.Errorf("failed to initialize brain: %w", err)
 		}
 
-		notes, err := b.ListNotes(tags)
-		if err != nil {
-			return fmt.Errorf("failed to list notes: %w", err)
+		filter := brain.TagFilter{
+			Any:     tags,
+			All:     allTags,
+			Exclude: excludeTags,
 		}
 
-		if len(notes) == 0 {
-			fmt.Println("No notes found.")
-			fmt.Println("Add your first note with: brain add \"your insight here\"")
-			return nil
+		if pending := len(b.PendingNotes()); pending > 0 {
+			fmt.Printf("⚠ %d note(s) are waiting for embeddings and won't show up in searches.\n", pending)
+			fmt.Println("  Run: brain embed --pending")
+			fmt.Println()
 		}
 
-		// Sort by timestamp, most recent first
-		sort.Slice(notes, func(i, j int) bool {
-			return notes[i].Timestamp.After(notes[j].Timestamp)
-		})
-
-		// Apply limit
-		if limit > 0 && limit < len(notes) {
-			notes = notes[:limit]
-		}
+		return browse(cmd, limit, func(offset, limit int) (int, bool, error) {
+			notes, total, err := b.ListNotesWithOptions(brain.ListOptions{
+				Filter: filter,
+				Saved:  saved,
+				Offset: offset,
+				Limit:  limit,
+			})
+			if err != nil {
+				return 0, false, fmt.Errorf("failed to list notes: %w", err)
+			}
 
-		fmt.Printf("Found %d note(s):\n\n", len(notes))
-		for i, note := range notes {
-			fmt.Printf("%d. [%s] %s\n", i+1, note.Timestamp.Format("2006-01-02 15:04"), note.Content)
-			if len(note.Tags) > 0 {
-				fmt.Printf("   Tags: %v\n", note.Tags)
+			switch {
+			case total == 0:
+				fmt.Println("No notes found.")
+				fmt.Println("Add your first note with: brain add \"your insight here\"")
+				return 0, false, nil
+			case len(notes) == 0:
+				fmt.Printf("No more notes (%d in total).\n", total)
+				return 0, false, nil
+			case len(notes) == total:
+				fmt.Printf("Found %d note(s):\n\n", total)
+			default:
+				fmt.Printf("Notes %d-%d of %d:\n\n", offset+1, offset+len(notes), total)
 			}
-			if note.Project != "" {
-				fmt.Printf("   Project: %s\n", note.Project)
+
+			for i, note := range notes {
+				printNote(offset+i+1, note)
 			}
-			fmt.Printf("   ID: %s\n\n", note.ID)
-		}
 
-		return nil
+			return len(notes), offset+len(notes) < total, nil
+		})
 	},
 }
 
+// printNote prints one numbered note
+func printNote(n int, note *brain.Note) {
+	fmt.Printf("%d. [%s] %s\n", n, note.Timestamp.Format("2006-01-02 15:04"), note.Content)
+	if len(note.Tags) > 0 {
+		fmt.Printf("   Tags: %v\n", note.Tags)
+	}
+	if note.Project != "" {
+		fmt.Printf("   Project: %s\n", note.Project)
+	}
+	if note.Pinned {
+		fmt.Println("   📌 Pinned")
+	}
+	if note.Pending {
+		fmt.Println("   Status: embedding pending")
+	}
+	fmt.Printf("   ID: %s\n\n", note.ID)
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
-	listCmd.Flags().IntP("limit", "l", 0, "Maximum number of notes to show (0 = all)")
-	listCmd.Flags().StringSliceP("tags", "t", []string{}, "Filter by tags")
+	listCmd.Flags().IntP("limit", "l", 0, "Maximum number of notes to show per page (0 = all, or 20 per page on a terminal)")
+	listCmd.Flags().StringSliceP("tags", "t", []string{}, "Filter by tags (notes with any of them)")
+	listCmd.Flags().StringSlice("all-tags", []string{}, "Only notes with all of these tags")
+	listCmd.Flags().StringSlice("exclude-tags", []string{}, "Skip notes with any of these tags")
+	listCmd.Flags().String("saved", "", "Only notes matched by this saved search")
+	addPagingFlags(listCmd)
 }
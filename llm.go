@@ -0,0 +1,322 @@
+package brain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrNoLLM is returned by commands that need a chat model when none is
+// configured
+var ErrNoLLM = errors.New("no LLM configured")
+
+const (
+	openAIBaseURL   = "https://api.openai.com/v1"
+	openAIChatModel = "gpt-4o-mini"
+)
+
+// Message is one turn of a conversation with an LLM
+type Message struct {
+	Role    string `json:"role"` // system, user or assistant
+	Content string `json:"content"`
+}
+
+// TokenUsage counts the tokens a completion used, as reported by the API
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Completion is an LLM's reply
+type Completion struct {
+	Content string
+	Model   string
+	Usage   TokenUsage
+}
+
+// LLM generates the next message of a conversation
+type LLM interface {
+	Complete(ctx context.Context, messages []Message) (*Completion, error)
+}
+
+// StreamingLLM is implemented by LLMs that can send their reply as it's
+// generated, so it can be shown straight away
+type StreamingLLM interface {
+	LLM
+	// Stream calls onToken with each piece of the reply as it arrives and
+	// returns the whole completion at the end
+	Stream(ctx context.Context, messages []Message, onToken func(string)) (*Completion, error)
+}
+
+// OpenAIChat is an LLM backed by an OpenAI-compatible chat completions
+// API. Besides OpenAI this covers Ollama, LM Studio, vLLM and most hosted
+// model gateways.
+type OpenAIChat struct {
+	apiKey string
+	model  string
+	url    string
+	client *http.Client
+}
+
+type openAIChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []Message            `json:"messages"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIChatChunk is one server-sent event of a streamed completion
+type openAIChatChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *TokenUsage `json:"usage"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage TokenUsage `json:"usage"`
+}
+
+// NewOpenAIChat creates a chat client for the API at baseURL, e.g.
+// https://api.openai.com/v1. The API key may be empty for local servers.
+func NewOpenAIChat(baseURL, model, apiKey string) *OpenAIChat {
+	return &OpenAIChat{
+		apiKey: apiKey,
+		model:  model,
+		url:    strings.TrimRight(baseURL, "/") + "/chat/completions",
+		client: &http.Client{},
+	}
+}
+
+func (c *OpenAIChat) Complete(ctx context.Context, messages []Message) (*Completion, error) {
+	if err := usageLogFrom(ctx).allow(c.model); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.post(ctx, openAIChatRequest{Model: c.model, Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, chatAPIError(resp, data)
+	}
+
+	completion, err := c.parseCompletion(data)
+	if err != nil {
+		return nil, err
+	}
+	recordChatUsage(ctx, messages, completion)
+	return completion, nil
+}
+
+// Stream asks for a server-sent event stream. Servers that reject
+// streaming requests, or answer them with a plain response, are handled
+// like Complete, with the whole reply passed to onToken at once.
+func (c *OpenAIChat) Stream(ctx context.Context, messages []Message, onToken func(string)) (*Completion, error) {
+	if err := usageLogFrom(ctx).allow(c.model); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.post(ctx, openAIChatRequest{
+		Model:         c.model,
+		Messages:      messages,
+		Stream:        true,
+		StreamOptions: &openAIStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		if streamingUnsupported(resp.StatusCode, data) {
+			return completeWhole(ctx, c, messages, onToken)
+		}
+		return nil, chatAPIError(resp, data)
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		completion, err := c.parseCompletion(data)
+		if err != nil {
+			return nil, err
+		}
+		recordChatUsage(ctx, messages, completion)
+		onToken(completion.Content)
+		return completion, nil
+	}
+
+	completion, err := c.readStream(resp.Body, onToken)
+	if err != nil {
+		return nil, err
+	}
+	recordChatUsage(ctx, messages, completion)
+	return completion, nil
+}
+
+// readStream reads "data: {...}" events until "data: [DONE]"
+func (c *OpenAIChat) readStream(body io.Reader, onToken func(string)) (*Completion, error) {
+	completion := &Completion{Model: c.model}
+	var content strings.Builder
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // blank separators, comments and other fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			completion.Content = content.String()
+			return completion, nil
+		}
+
+		var chunk openAIChatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("invalid chat stream event: %w", err)
+		}
+		if chunk.Model != "" {
+			completion.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			completion.Usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				onToken(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("chat stream ended early")
+}
+
+func (c *OpenAIChat) post(ctx context.Context, request openAIChatRequest) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	return c.client.Do(req)
+}
+
+// streamingUnsupported reports whether an error response is a server
+// turning down streaming, rather than a request it would refuse anyway
+func streamingUnsupported(status int, body []byte) bool {
+	switch status {
+	case http.StatusNotImplemented:
+		return true
+	case http.StatusBadRequest:
+		return bytes.Contains(bytes.ToLower(body), []byte("stream"))
+	}
+	return false
+}
+
+func chatAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := newAPIError(resp, body)
+	apiErr.Service = "chat"
+	return apiErr
+}
+
+func (c *OpenAIChat) parseCompletion(data []byte) (*Completion, error) {
+	var result openAIChatResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid chat response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("chat response contained no choices")
+	}
+
+	model := result.Model
+	if model == "" {
+		model = c.model
+	}
+	return &Completion{
+		Content: result.Choices[0].Message.Content,
+		Model:   model,
+		Usage:   result.Usage,
+	}, nil
+}
+
+// recordChatUsage logs a completion in the usage log, if ctx has one.
+// Servers that don't report usage get an estimate from the text.
+func recordChatUsage(ctx context.Context, messages []Message, completion *Completion) {
+	record := UsageRecord{
+		Kind:             "chat",
+		Model:            completion.Model,
+		PromptTokens:     completion.Usage.PromptTokens,
+		CompletionTokens: completion.Usage.CompletionTokens,
+	}
+	if completion.Usage == (TokenUsage{}) {
+		for _, m := range messages {
+			record.PromptTokens += estimateTokens(m.Content)
+		}
+		record.CompletionTokens = estimateTokens(completion.Content)
+		record.Estimated = true
+	}
+	usageLogFrom(ctx).record(record)
+}
+
+// completeWhole runs a completion without streaming and passes the whole
+// reply to onToken
+func completeWhole(ctx context.Context, llm LLM, messages []Message, onToken func(string)) (*Completion, error) {
+	completion, err := llm.Complete(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	onToken(completion.Content)
+	return completion, nil
+}
+
+// newLLM builds the chat model from the config. It returns nil when
+// OpenAI is selected but no API key is set, so commands can fall back to
+// showing notes.
+func newLLM(config LLMConfig) LLM {
+	apiKey := os.Getenv(config.APIKeyEnv)
+	if apiKey == "" && config.BaseURL == openAIBaseURL {
+		return nil
+	}
+	return NewOpenAIChat(config.BaseURL, config.Model, apiKey)
+}
@@ -0,0 +1,244 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newChatStub serves an OpenAI-compatible chat completions endpoint that
+// replies with reply and records the last request
+func newChatStub(t *testing.T, reply string) (*httptest.Server, *openAIChatRequest) {
+	t.Helper()
+
+	var last openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "Incorrect API key", "code": "invalid_api_key"}}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&last)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model": last.Model + "-0613",
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": reply}},
+			},
+			"usage": map[string]int{"prompt_tokens": 120, "completion_tokens": 30},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+func TestOpenAIChat(t *testing.T) {
+	server, last := newChatStub(t, "Use connection pooling [1].")
+	llm := NewOpenAIChat(server.URL+"/v1/", "test-model", "test-key")
+
+	messages := []Message{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "How?"}}
+	completion, err := llm.Complete(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if completion.Content != "Use connection pooling [1]." {
+		t.Errorf("Unexpected content %q", completion.Content)
+	}
+	if completion.Model != "test-model-0613" {
+		t.Errorf("Expected the model reported by the API, got %q", completion.Model)
+	}
+	if completion.Usage != (TokenUsage{PromptTokens: 120, CompletionTokens: 30}) {
+		t.Errorf("Unexpected usage %+v", completion.Usage)
+	}
+	if last.Model != "test-model" || len(last.Messages) != 2 || last.Messages[1].Content != "How?" {
+		t.Errorf("Unexpected request %+v", *last)
+	}
+}
+
+func TestOpenAIChatErrors(t *testing.T) {
+	server, _ := newChatStub(t, "")
+	llm := NewOpenAIChat(server.URL+"/v1", "test-model", "wrong-key")
+
+	_, err := llm.Complete(context.Background(), []Message{{Role: "user", Content: "Hi"}})
+	if !errors.Is(err, ErrAuth) {
+		t.Fatalf("Expected ErrAuth, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Service != "chat" {
+		t.Errorf("Expected a chat APIError, got %v", err)
+	}
+}
+
+func TestNewLLM(t *testing.T) {
+	config := DefaultConfig().LLM
+	config.APIKeyEnv = "BRAIN_TEST_LLM_KEY"
+
+	t.Setenv("BRAIN_TEST_LLM_KEY", "")
+	if llm := newLLM(config); llm != nil {
+		t.Error("Expected no LLM for OpenAI without an API key")
+	}
+
+	// Local servers don't need a key
+	config.BaseURL = "http://localhost:11434/v1"
+	if llm := newLLM(config); llm == nil {
+		t.Error("Expected an LLM for a local server without an API key")
+	}
+}
+
+// newStreamStub serves a chat completions endpoint that streams tokens as
+// server-sent events. With block set it stops after the first token and
+// waits for the client to hang up.
+func newStreamStub(t *testing.T, tokens []string, block bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("Expected a streaming request with usage, got %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, token := range tokens {
+			chunk, _ := json.Marshal(map[string]interface{}{
+				"model":   "stream-model",
+				"choices": []map[string]interface{}{{"delta": map[string]string{"content": token}}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+			w.(http.Flusher).Flush()
+			if block && i == 0 {
+				<-r.Context().Done()
+				return
+			}
+		}
+		fmt.Fprint(w, ": keep-alive comment\n\n")
+		fmt.Fprint(w, `data: {"choices": [], "usage": {"prompt_tokens": 40, "completion_tokens": 3}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	server := newStreamStub(t, []string{"Pool ", "connections ", "[1]."}, false)
+	llm := NewOpenAIChat(server.URL, "test-model", "")
+
+	var tokens []string
+	completion, err := llm.Stream(context.Background(), []Message{{Role: "user", Content: "How?"}}, func(token string) {
+		tokens = append(tokens, token)
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	if len(tokens) != 3 || completion.Content != "Pool connections [1]." {
+		t.Errorf("Expected 3 tokens making up the answer, got %q and %q", tokens, completion.Content)
+	}
+	if completion.Model != "stream-model" || completion.Usage.CompletionTokens != 3 {
+		t.Errorf("Expected the model and usage from the stream, got %+v", completion)
+	}
+}
+
+func TestOpenAIChatStreamCancel(t *testing.T) {
+	server := newStreamStub(t, []string{"Pool ", "connections"}, true)
+	llm := NewOpenAIChat(server.URL, "test-model", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var received strings.Builder
+	_, err := llm.Stream(ctx, []Message{{Role: "user", Content: "How?"}}, func(token string) {
+		received.WriteString(token)
+		cancel() // like Ctrl-C after the first token
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if received.String() != "Pool " {
+		t.Errorf("Expected only the first token, got %q", received.String())
+	}
+}
+
+func TestOpenAIChatStreamBadRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, `{"error": {"message": "This model's maximum context length is 8192 tokens"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	// A 400 about something else is reported, not retried without streaming
+	llm := NewOpenAIChat(server.URL, "test-model", "")
+	_, err := llm.Stream(context.Background(), []Message{{Role: "user", Content: "How?"}}, func(string) {})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || !strings.Contains(apiErr.Message, "context length") {
+		t.Fatalf("Expected the 400 with its message, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no fallback request, got %d requests", requests)
+	}
+}
+
+func TestOpenAIChatStreamFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, stream bool)
+	}{
+		{
+			name: "streaming rejected",
+			handler: func(w http.ResponseWriter, stream bool) {
+				if stream {
+					http.Error(w, `{"error": {"message": "stream is not supported"}}`, http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "Whole answer"}}]}`)
+			},
+		},
+		{
+			name: "stream flag ignored",
+			handler: func(w http.ResponseWriter, stream bool) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "Whole answer"}}]}`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req openAIChatRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				tt.handler(w, req.Stream)
+			}))
+			defer server.Close()
+
+			var tokens []string
+			llm := NewOpenAIChat(server.URL, "test-model", "")
+			usage := &usageLog{path: usagePath(t.TempDir())}
+			ctx := withUsageLog(context.Background(), usage)
+			completion, err := llm.Stream(ctx, []Message{{Role: "user", Content: "How?"}}, func(token string) {
+				tokens = append(tokens, token)
+			})
+			if err != nil {
+				t.Fatalf("Stream failed: %v", err)
+			}
+			if completion.Content != "Whole answer" || len(tokens) != 1 || tokens[0] != "Whole answer" {
+				t.Errorf("Expected the whole answer as one token, got %q", tokens)
+			}
+
+			// The call is logged once, whichever way the answer came
+			if records, _ := readUsage(usage.path, time.Time{}); len(records) != 1 || records[0].Model != "test-model" {
+				t.Errorf("Expected one usage record, got %+v", records)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package brain
+
+// mmrPoolFactor bounds how many of the top results MMR reorders, as a
+// multiple of the page size
+const mmrPoolFactor = 10
+
+// diversify reorders the top results with Maximal Marginal Relevance,
+// repeatedly picking the result that maximizes
+//
+//	λ·relevance − (1−λ)·max similarity to the results already picked
+//
+// where λ = 1 − diversity. Diversity 0 keeps the order by relevance; higher
+// values push near-duplicates of earlier results down in favour of
+// notes about something else. Only the top poolSize results are reordered
+// (all of them when it's 0) and the rest follow by relevance. The pool
+// mustn't depend on the page, so every page slices the same order.
+func (b *Brain) diversify(results []SearchResult, diversity float64, poolSize int) []SearchResult {
+	if diversity <= 0 || len(results) < 2 {
+		return results
+	}
+
+	lambda := 1 - diversity
+	pool, rest := results, []SearchResult(nil)
+	if poolSize > 0 && len(pool) > poolSize {
+		pool, rest = results[:poolSize], results[poolSize:]
+	}
+
+	embeddings := make([][]float32, len(pool))
+	for i, r := range pool {
+		embeddings[i] = b.storedEmbedding(r.Note)
+	}
+
+	// redundancy[i] is the highest similarity of candidate i to any
+	// result picked so far, updated as each one is picked
+	redundancy := make([]float64, len(pool))
+	picked := make([]bool, len(pool))
+	selected := make([]SearchResult, 0, len(results))
+
+	for len(selected) < len(pool) {
+		best, bestScore := -1, 0.0
+		for i, r := range pool {
+			if picked[i] {
+				continue
+			}
+			score := lambda*r.Similarity - (1-lambda)*redundancy[i]
+			if best == -1 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked[best] = true
+		r := pool[best]
+		r.Score.Redundancy = redundancy[best]
+		selected = append(selected, r)
+
+		for i := range pool {
+			if picked[i] || embeddings[i] == nil || embeddings[best] == nil {
+				continue
+			}
+			if similarity := cosineSimilarity(embeddings[i], embeddings[best]); similarity > redundancy[i] {
+				redundancy[i] = similarity
+			}
+		}
+	}
+
+	return append(selected, rest...)
+}
+
+// storedEmbedding returns the note's embedding as held by the vector store
+func (b *Brain) storedEmbedding(note *Note) []float32 {
+	if source, ok := b.vectorStore.(embeddingSource); ok {
+		return source.Embedding(note)
+	}
+	return note.Embedding
+}
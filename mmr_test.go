@@ -0,0 +1,96 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiversifyCoversDistinctNotes(t *testing.T) {
+	for _, precision := range []Precision{PrecisionFloat32, PrecisionInt8} {
+		t.Run(string(precision), func(t *testing.T) {
+			b := newTestBrain(t, NewLocalEmbedder())
+			b.vectorStore, _ = NewSimpleVectorStore(b.dataDir, VectorStoreOptions{Precision: precision})
+
+			for _, content := range []string{
+				"Cache invalidation: expire the cache entry when the row changes",
+				"Cache invalidation: expire cache entries when the row changes",
+				"Cache invalidation: expire the cache entry whenever the row changes",
+				"Cache warming: preload the cache on deploy so the first requests are fast",
+			} {
+				if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+					t.Fatalf("AddNote failed: %v", err)
+				}
+			}
+
+			plain, err := b.SearchWithOptions("cache invalidation", SearchOptions{Limit: 2})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if containsContent(plain, "Cache warming") {
+				t.Fatalf("Expected near-duplicates to fill the results without diversity, got %+v", plain)
+			}
+
+			diverse, err := b.SearchWithOptions("cache invalidation", SearchOptions{Limit: 2, Diversity: 0.7})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(diverse) != 2 || diverse[0].Note != plain[0].Note {
+				t.Fatalf("Expected the best match to stay first, got %+v", diverse)
+			}
+			if !containsContent(diverse, "Cache warming") {
+				t.Errorf("Expected diversity to bring in the distinct note, got %q and %q",
+					diverse[0].Note.Content, diverse[1].Note.Content)
+			}
+			if diverse[1].Score.Redundancy <= 0 {
+				t.Errorf("Expected the second result to record its redundancy, got %+v", diverse[1].Score)
+			}
+		})
+	}
+}
+
+func containsContent(results []SearchResult, prefix string) bool {
+	for _, r := range results {
+		if strings.HasPrefix(r.Note.Content, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiversifyPagesDontOverlap(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+
+	// More results than one page's MMR pool: near-duplicates that match
+	// best, then distinct notes that a bigger pool would pull forward
+	var notes []string
+	for i := 0; i < 35; i++ {
+		notes = append(notes, fmt.Sprintf("Cache invalidation expire entry on row change %d", i))
+	}
+	for _, topic := range []string{"warming on deploy", "eviction with LRU", "stampede locks", "TTL jitter", "sharding by key"} {
+		notes = append(notes, "Cache "+topic)
+	}
+	for _, content := range notes {
+		if err := b.AddNote(&Note{Content: content, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	seen := make(map[*Note]bool)
+	for offset := 0; offset < len(notes); offset += 2 {
+		page, err := b.SearchWithOptions("cache invalidation expire entry", SearchOptions{Limit: 2, Offset: offset, Diversity: 0.7, Mode: SearchModeKeyword})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		for _, r := range page {
+			if seen[r.Note] {
+				t.Errorf("%q is on more than one page", r.Note.Content)
+			}
+			seen[r.Note] = true
+		}
+	}
+	if len(seen) != len(notes) {
+		t.Errorf("Expected the pages to cover all %d notes, got %d", len(notes), len(seen))
+	}
+}
@@ -0,0 +1,91 @@
+package brain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// UpdateNote replaces a note's content and re-embeds it. As with AddNote,
+// the change is saved even if embedding fails, and ErrEmbeddingPending is
+// returned.
+func (b *Brain) UpdateNote(note *Note, content string) error {
+	// Both indexes must drop the note before its content changes
+	if err := b.vectorStore.Remove(note); err != nil {
+		return err
+	}
+	b.keywords.Remove(note)
+
+	note.Content = content
+	note.Embedding = nil
+	note.Chunks = nil
+	embedErr := b.embedNote(note)
+
+	if err := b.vectorStore.Add(note); err != nil {
+		return err
+	}
+	b.keywords.Add(note)
+
+	if err := b.saveNotes(); err != nil {
+		return err
+	}
+
+	if embedErr != nil {
+		return fmt.Errorf("%w: %w", ErrEmbeddingPending, embedErr)
+	}
+	return nil
+}
+
+// DeleteNote removes a note for good
+func (b *Brain) DeleteNote(note *Note) error {
+	if err := b.vectorStore.Remove(note); err != nil {
+		return err
+	}
+	b.keywords.Remove(note)
+
+	return b.saveNotes()
+}
+
+// mentionPattern matches "@<id>" mentions of other notes, where the ID
+// may be shortened to a prefix of at least 6 characters
+var mentionPattern = regexp.MustCompile(`@([0-9a-fA-F][0-9a-fA-F-]{5,35})\b`)
+
+// LinkedNotes returns the notes this note mentions with @id, followed by
+// the notes that mention it. Mentions that match no note, or more than
+// one, are ignored.
+func (b *Brain) LinkedNotes(note *Note) []*Note {
+	var linked []*Note
+	seen := map[*Note]bool{note: true}
+
+	for _, target := range b.mentions(note) {
+		if !seen[target] {
+			seen[target] = true
+			linked = append(linked, target)
+		}
+	}
+
+	for _, other := range b.vectorStore.GetAllNotes() {
+		if seen[other] {
+			continue
+		}
+		for _, target := range b.mentions(other) {
+			if target == note {
+				seen[other] = true
+				linked = append(linked, other)
+				break
+			}
+		}
+	}
+
+	return linked
+}
+
+// mentions resolves the @id mentions in a note's content
+func (b *Brain) mentions(note *Note) []*Note {
+	var targets []*Note
+	for _, match := range mentionPattern.FindAllStringSubmatch(note.Content, -1) {
+		if target, err := b.FindNote(match[1]); err == nil {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
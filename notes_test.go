@@ -0,0 +1,107 @@
+package brain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateNote(t *testing.T) {
+	for _, precision := range []Precision{PrecisionFloat32, PrecisionInt8} {
+		t.Run(string(precision), func(t *testing.T) {
+			b := newTestBrain(t, NewLocalEmbedder())
+			b.vectorStore, _ = NewSimpleVectorStore(b.dataDir, VectorStoreOptions{Precision: precision})
+
+			note := &Note{Content: "Use exponential backoff for retries", Timestamp: time.Now()}
+			if err := b.AddNote(note); err != nil {
+				t.Fatalf("AddNote failed: %v", err)
+			}
+
+			if err := b.UpdateNote(note, "Kubernetes liveness probes restart stuck pods"); err != nil {
+				t.Fatalf("UpdateNote failed: %v", err)
+			}
+
+			if got := len(b.vectorStore.GetAllNotes()); got != 1 {
+				t.Fatalf("Expected 1 note after update, got %d", got)
+			}
+			if results, _ := b.SearchWithOptions("backoff", SearchOptions{Limit: 5, Mode: SearchModeKeyword}); len(results) != 0 {
+				t.Errorf("Expected old content to be gone from the keyword index, got %d results", len(results))
+			}
+			results, err := b.SearchWithOptions("liveness probes", SearchOptions{Limit: 5, Mode: SearchModeSemantic})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 1 || results[0].Note != note {
+				t.Errorf("Expected the updated note to be found by its new content, got %+v", results)
+			}
+		})
+	}
+}
+
+func TestDeleteNote(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	keep := &Note{Content: "Keep this one", Timestamp: time.Now()}
+	drop := &Note{Content: "Drop this one", Timestamp: time.Now()}
+	for _, note := range []*Note{keep, drop} {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	if err := b.DeleteNote(drop); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if err := b.loadNotes(); err != nil {
+		t.Fatalf("Failed to reload notes: %v", err)
+	}
+
+	notes := b.vectorStore.GetAllNotes()
+	if len(notes) != 1 || notes[0].ID != keep.ID {
+		t.Errorf("Expected only the kept note after reload, got %d notes", len(notes))
+	}
+	if results, _ := b.SearchWithOptions("drop", SearchOptions{Limit: 5, Mode: SearchModeKeyword}); len(results) != 0 {
+		t.Errorf("Expected the deleted note to be gone from the keyword index")
+	}
+}
+
+func TestLinkedNotes(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	notes := []*Note{
+		{ID: "aaaaaaaa-1111", Content: "Deploy checklist"},
+		{ID: "bbbbbbbb-2222", Content: "Rollback steps, see @aaaaaaaa"},
+		{ID: "cccccccc-3333", Content: "Incident review, caused by @bbbbbbbb-2222 and @ffffffff"},
+		{ID: "dddddddd-4444", Content: "Unrelated, mail me at ops@example.com"},
+	}
+	for _, note := range notes {
+		note.Timestamp = time.Now()
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		note     *Note
+		expected []string
+	}{
+		{notes[0], []string{"bbbbbbbb-2222"}},                  // mentioned by
+		{notes[1], []string{"aaaaaaaa-1111", "cccccccc-3333"}}, // mentions, then mentioned by
+		{notes[2], []string{"bbbbbbbb-2222"}},                  // unknown @ffffffff ignored
+		{notes[3], nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, note := range b.LinkedNotes(tt.note) {
+			got = append(got, note.ID)
+		}
+		if len(got) != len(tt.expected) {
+			t.Errorf("LinkedNotes(%s) = %v, want %v", tt.note.ID, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("LinkedNotes(%s) = %v, want %v", tt.note.ID, got, tt.expected)
+				break
+			}
+		}
+	}
+}
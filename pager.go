@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPageSize is used when paging output that has no --limit
+const defaultPageSize = 20
+
+// pageSource prints one page of at most limit items starting at offset
+// and reports how many it printed and whether more follow
+type pageSource func(offset, limit int) (shown int, more bool, err error)
+
+// addPagingFlags registers --page, --offset and --no-pager
+func addPagingFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 0, "Show only this page of results (1 = first)")
+	cmd.Flags().Int("offset", 0, "Skip this many results")
+	cmd.Flags().Bool("no-pager", false, "Print results without pausing between pages")
+}
+
+// browse prints pages from show. On a terminal it pauses after each page
+// and fetches the next one on Enter; with --page or --offset, or when the
+// output isn't a terminal, it prints a single page.
+func browse(cmd *cobra.Command, pageSize int, show pageSource) error {
+	page, _ := cmd.Flags().GetInt("page")
+	offset, _ := cmd.Flags().GetInt("offset")
+	noPager, _ := cmd.Flags().GetBool("no-pager")
+
+	if page < 0 || offset < 0 {
+		return fmt.Errorf("--page and --offset must not be negative")
+	}
+	if page > 0 && offset > 0 {
+		return fmt.Errorf("use either --page or --offset, not both")
+	}
+
+	explicit := page > 0 || offset > 0
+	interactive := !explicit && !noPager && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+	if pageSize <= 0 && (explicit || interactive) {
+		pageSize = defaultPageSize
+	}
+	if page > 0 {
+		offset = (page - 1) * pageSize
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		shown, more, err := show(offset, pageSize)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+		offset += shown
+
+		if !interactive {
+			fmt.Printf("More results: use --page %d or --offset %d\n", offset/pageSize+1, offset)
+			return nil
+		}
+
+		fmt.Print("-- More -- Enter for the next page, q to quit: ")
+		answer, err := input.ReadString('\n')
+		if err != nil || strings.EqualFold(strings.TrimSpace(answer), "q") {
+			fmt.Println()
+			return nil
+		}
+		fmt.Println()
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,68 @@
+package brain
+
+import "errors"
+
+// ErrEmbeddingPending is returned by AddNote when the note was saved but
+// its embedding couldn't be generated yet
+var ErrEmbeddingPending = errors.New("note saved, embedding pending")
+
+// embedNote generates the note's embedding, marking it pending on failure.
+// Long notes are split into passages which are embedded separately.
+func (b *Brain) embedNote(note *Note) error {
+	passages := splitIntoChunks(note.Content, maxChunkChars, chunkOverlapChars)
+
+	chunks := make([]Chunk, 0, len(passages))
+	for _, passage := range passages {
+		embedding, err := b.embed(passage)
+		if err != nil {
+			note.Pending = true
+			return err
+		}
+		chunks = append(chunks, Chunk{Text: passage, Embedding: embedding})
+	}
+
+	if len(chunks) == 1 {
+		note.Embedding = chunks[0].Embedding
+		note.Chunks = nil
+	} else {
+		note.Embedding = meanEmbedding(chunks)
+		note.Chunks = chunks
+	}
+	note.Pending = false
+	b.embeddingsChanged = true
+	return nil
+}
+
+// PendingNotes returns notes that are saved but not yet searchable
+// because their embedding is missing
+func (b *Brain) PendingNotes() []*Note {
+	var pending []*Note
+	for _, note := range b.vectorStore.GetAllNotes() {
+		if note.Pending {
+			pending = append(pending, note)
+		}
+	}
+	return pending
+}
+
+// EmbedPending retries embedding for all pending notes. It stops at the
+// first failure and returns how many notes were embedded before it.
+func (b *Brain) EmbedPending() (int, error) {
+	embedded := 0
+	var embedErr error
+
+	for _, note := range b.PendingNotes() {
+		if embedErr = b.embedNote(note); embedErr != nil {
+			break
+		}
+		embedded++
+	}
+
+	if embedded > 0 {
+		if err := b.saveNotes(); err != nil {
+			return embedded, err
+		}
+	}
+
+	return embedded, embedErr
+}
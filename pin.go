@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [note-id]",
+	Short: "Pin a note so it ranks higher",
+	Long: `Pin a note so it ranks higher in search, ask and context results.
+A pinned note still has to match the query to show up.
+
+The ID can be shortened to any unique prefix, as shown by brain list.
+
+Examples:
+  brain pin 3f2a9c1e
+  brain unpin 3f2a9c1e`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPinned(args[0], true)
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin [note-id]",
+	Short: "Unpin a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPinned(args[0], false)
+	},
+}
+
+func setPinned(id string, pinned bool) error {
+	b, err := brain.New()
+	if err != nil {
+		return fmt.Errorf("failed to initialize brain: %w", err)
+	}
+
+	note, err := b.FindNote(id)
+	if err != nil {
+		return err
+	}
+
+	if err := b.SetPinned(note, pinned); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+
+	if pinned {
+		fmt.Printf("📌 Pinned: %s\n", note.Content)
+	} else {
+		fmt.Printf("✓ Unpinned: %s\n", note.Content)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
@@ -0,0 +1,79 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsPerToken is a rough average for English text, close enough to
+// keep prompts inside a budget without shipping a tokenizer
+const charsPerToken = 4
+
+// minNoteTokens is the smallest excerpt of a note worth sending; a note
+// that doesn't fit even this much is left out
+const minNoteTokens = 50
+
+const askSystemPrompt = `You answer questions using only the user's own notes, which are numbered below.
+Cite the notes each statement comes from with their numbers in square brackets, like [1] or [2][3].
+If the notes don't answer the question, say so rather than guessing.
+Be concise.`
+
+const groundedSystemPrompt = `You answer questions using only the user's own notes, which are listed below with their IDs.
+Only state what the notes say. End every sentence with the IDs of the notes it comes from in square brackets, like [3f2a9c1e] or [3f2a9c1e, 9b07d4aa].
+Never cite an ID that isn't listed. If the notes don't answer the question, reply exactly: ` + NoAnswer
+
+// estimateTokens approximates how many tokens text uses
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// packNotes formats results as numbered sources, best first, until the
+// token budget is used up. A long note that doesn't fit is sent as its
+// best matching passage, or cut short. It returns the text and the
+// results it includes, so source n is packed[n-1].
+func packNotes(results []SearchResult, budget int) (string, []SearchResult) {
+	var sb strings.Builder
+	var packed []SearchResult
+
+	for _, r := range results {
+		header := fmt.Sprintf("[%d] id %s · %s", len(packed)+1, ShortID(r.Note.ID), r.Note.Timestamp.Format("2006-01-02"))
+		if len(r.Note.Tags) > 0 {
+			header += " · tags: " + strings.Join(r.Note.Tags, ", ")
+		}
+		if r.Note.Project != "" {
+			header += " · project: " + r.Note.Project
+		}
+
+		remaining := budget - estimateTokens(header) - 1
+		if remaining < minNoteTokens {
+			break
+		}
+
+		content := r.Note.Content
+		if estimateTokens(content) > remaining && r.Passage != "" {
+			content = r.Passage
+		}
+		if estimateTokens(content) > remaining {
+			content = truncateRunes(content, remaining*charsPerToken-len("…")) + "…"
+		}
+
+		entry := header + "\n" + strings.TrimSpace(content) + "\n\n"
+		sb.WriteString(entry)
+		budget -= estimateTokens(entry)
+		packed = append(packed, r)
+	}
+
+	return strings.TrimSpace(sb.String()), packed
+}
+
+// truncateRunes cuts s to at most n bytes without splitting a character
+func truncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,64 @@
+package brain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackNotes(t *testing.T) {
+	when := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	short := &Note{ID: "aaaaaaaa-1111", Content: "Pool database connections", Tags: []string{"db"}, Timestamp: when}
+	long := &Note{ID: "bbbbbbbb-2222", Content: strings.Repeat("Long design doc. ", 200), Timestamp: when}
+	other := &Note{ID: "cccccccc-3333", Content: "Cache hot queries", Project: "api", Timestamp: when}
+
+	results := []SearchResult{{Note: short}, {Note: long, Passage: "The relevant section"}, {Note: other}}
+
+	t.Run("everything fits", func(t *testing.T) {
+		text, packed := packNotes(results, 10000)
+		if len(packed) != 3 {
+			t.Fatalf("Expected 3 notes, got %d", len(packed))
+		}
+		for _, want := range []string{
+			"[1] id aaaaaaaa · 2026-03-01 · tags: db\nPool database connections",
+			"[2] id bbbbbbbb",
+			"[3] id cccccccc · 2026-03-01 · project: api\nCache hot queries",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("Expected %q in:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("long note sent as its passage", func(t *testing.T) {
+		text, packed := packNotes(results, 300)
+		if len(packed) != 3 {
+			t.Fatalf("Expected 3 notes, got %d", len(packed))
+		}
+		if !strings.Contains(text, "The relevant section") || strings.Contains(text, "Long design doc.") {
+			t.Errorf("Expected the passage instead of the long note:\n%s", text)
+		}
+	})
+
+	t.Run("budget stops packing", func(t *testing.T) {
+		text, packed := packNotes([]SearchResult{{Note: long}, {Note: short}}, 100)
+		if len(packed) != 1 || packed[0].Note != long {
+			t.Fatalf("Expected only the truncated long note, got %d notes", len(packed))
+		}
+		if !strings.HasSuffix(text, "…") {
+			t.Errorf("Expected the long note to be cut short")
+		}
+		if tokens := estimateTokens(text); tokens > 100 {
+			t.Errorf("Packed %d tokens, over the budget of 100", tokens)
+		}
+	})
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("héllo", 2); got != "h" {
+		t.Errorf("Expected the cut not to split é, got %q", got)
+	}
+	if got := truncateRunes("hello", 10); got != "hello" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+}
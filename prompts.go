@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "Manage the prompts sent to the chat model",
+	Long: `The prompts used by ask, chat and digest are Go text/template files.
+Override one by saving a file named after it in ~/.brain/prompts/, e.g.
+~/.brain/prompts/ask.tmpl, that defines a "system" and a "user" template:
+they become the system and user messages sent to the model.
+
+Templates can use these variables:
+` + promptVariableHelp() + `
+
+Templates are checked when they're loaded. A mistake such as a misspelt
+variable makes the commands using that prompt fail with the error, which
+brain prompts list also shows; other commands keep working. Start from
+the default with brain prompts show --default.
+
+Examples:
+  brain prompts list
+  mkdir -p ~/.brain/prompts
+  brain prompts show ask --default > ~/.brain/prompts/ask.tmpl
+  brain prompts show ask
+  brain prompts reset ask`,
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the prompts and whether they are overridden",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		infos, err := brain.ListPrompts()
+		if err != nil {
+			return err
+		}
+
+		for _, info := range infos {
+			status := "default"
+			if info.Custom {
+				status = "custom: " + info.Path
+			}
+			fmt.Printf("📝 %-7s %s\n", info.Name, info.Description)
+			fmt.Printf("  %s\n", status)
+			if info.Err != nil {
+				fmt.Printf("  ⚠ %v\n", info.Err)
+			}
+		}
+
+		fmt.Println("\nVariables:")
+		fmt.Println(promptVariableHelp())
+		return nil
+	},
+}
+
+var promptsShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Print a prompt template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useDefault, _ := cmd.Flags().GetBool("default")
+
+		var source string
+		var err error
+		if useDefault {
+			source, err = brain.DefaultPrompt(args[0])
+		} else {
+			source, _, err = brain.PromptSource(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("%w (prompts are %s)", err, strings.Join(brain.PromptNames(), ", "))
+		}
+
+		fmt.Print(source)
+		if !strings.HasSuffix(source, "\n") {
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var promptsResetCmd = &cobra.Command{
+	Use:   "reset [name...]",
+	Short: "Go back to the default prompts",
+	Long: `Remove the files overriding prompts, so the defaults are used again.
+With no names every prompt is reset.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := args
+		if len(names) == 0 {
+			names = brain.PromptNames()
+		}
+
+		reset := 0
+		for _, name := range names {
+			removed, err := brain.ResetPrompt(name)
+			if err != nil {
+				return fmt.Errorf("failed to reset %s: %w", name, err)
+			}
+			if removed {
+				fmt.Printf("✓ Reset %s to the default\n", name)
+				reset++
+			}
+		}
+		if reset == 0 {
+			fmt.Println("✓ Already using the default prompts")
+		}
+		return nil
+	},
+}
+
+// promptVariableHelp lists the template variables, one per line
+func promptVariableHelp() string {
+	var lines []string
+	for _, v := range brain.PromptVariables {
+		lines = append(lines, fmt.Sprintf("  %-14s %s", "{{."+v[0]+"}}", v[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	rootCmd.AddCommand(promptsCmd)
+	promptsCmd.AddCommand(promptsListCmd, promptsShowCmd, promptsResetCmd)
+	promptsShowCmd.Flags().Bool("default", false, "Print the built-in prompt even if it is overridden")
+}
@@ -0,0 +1,253 @@
+package brain
+
+import (
+	"math"
+	"sync"
+)
+
+// Precision is how embeddings are held in the vector store
+type Precision string
+
+const (
+	PrecisionFloat32 Precision = "float32" // exact, 4 bytes per dimension
+	PrecisionFloat16 Precision = "float16" // 2 bytes per dimension, near-exact
+	PrecisionInt8    Precision = "int8"    // 1 byte per dimension plus a per-vector scale
+)
+
+// quantizedVector is a compressed embedding that can be scored against
+// a full precision query without decompressing it first
+type quantizedVector interface {
+	dot(query []float32) float64
+	norm() float64
+	decode() []float32 // approximate float32 copy
+}
+
+// quantize compresses v at the given precision
+func quantize(v []float32, precision Precision) quantizedVector {
+	switch precision {
+	case PrecisionFloat16:
+		return newFloat16Vector(v)
+	case PrecisionInt8:
+		return newInt8Vector(v)
+	}
+	return nil
+}
+
+// quantizedCosine is cosineSimilarity for a compressed vector
+func quantizedCosine(query []float32, queryNorm float64, v quantizedVector) float64 {
+	if queryNorm == 0 || v.norm() == 0 {
+		return 0
+	}
+	return v.dot(query) / (queryNorm * v.norm())
+}
+
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// int8Vector stores each component as round(x / scale), where scale maps
+// the largest magnitude in the vector onto 127
+type int8Vector struct {
+	values []int8
+	scale  float32
+	length float64
+}
+
+func newInt8Vector(v []float32) *int8Vector {
+	var maxAbs float32
+	for _, x := range v {
+		if x < 0 {
+			x = -x
+		}
+		if x > maxAbs {
+			maxAbs = x
+		}
+	}
+
+	q := &int8Vector{values: make([]int8, len(v))}
+	if maxAbs == 0 {
+		return q
+	}
+	q.scale = maxAbs / 127
+
+	for i, x := range v {
+		q.values[i] = int8(math.Round(float64(x / q.scale)))
+	}
+	q.setLength()
+
+	return q
+}
+
+// restoreInt8Vector rebuilds a vector from saved values and scale
+func restoreInt8Vector(values []int8, scale float32) *int8Vector {
+	q := &int8Vector{values: values, scale: scale}
+	q.setLength()
+	return q
+}
+
+func (q *int8Vector) setLength() {
+	var sum float64
+	for _, x := range q.values {
+		d := float64(x) * float64(q.scale)
+		sum += d * d
+	}
+	q.length = math.Sqrt(sum)
+}
+
+func (q *int8Vector) dot(query []float32) float64 {
+	if len(query) != len(q.values) {
+		return 0
+	}
+	var sum float64
+	for i, x := range q.values {
+		sum += float64(query[i]) * float64(x)
+	}
+	return sum * float64(q.scale)
+}
+
+func (q *int8Vector) norm() float64 {
+	return q.length
+}
+
+func (q *int8Vector) decode() []float32 {
+	v := make([]float32, len(q.values))
+	for i, x := range q.values {
+		v[i] = float32(x) * q.scale
+	}
+	return v
+}
+
+// float16Vector stores each component as an IEEE 754 half precision float
+type float16Vector struct {
+	values []uint16
+	length float64
+}
+
+// float16Table maps every half float to its float32 value, which is much
+// faster than converting each component while scanning
+var (
+	float16Table     []float32
+	float16TableOnce sync.Once
+)
+
+func initFloat16Table() {
+	float16TableOnce.Do(func() {
+		float16Table = make([]float32, 1<<16)
+		for h := range float16Table {
+			float16Table[h] = float16ToFloat32(uint16(h))
+		}
+	})
+}
+
+func newFloat16Vector(v []float32) *float16Vector {
+	initFloat16Table()
+
+	q := &float16Vector{values: make([]uint16, len(v))}
+
+	var sum float64
+	for i, x := range v {
+		q.values[i] = float32ToFloat16(x)
+		d := float64(float16ToFloat32(q.values[i]))
+		sum += d * d
+	}
+	q.length = math.Sqrt(sum)
+
+	return q
+}
+
+// restoreFloat16Vector rebuilds a vector from saved values
+func restoreFloat16Vector(values []uint16) *float16Vector {
+	initFloat16Table()
+
+	q := &float16Vector{values: values}
+	q.length = vectorNorm(q.decode())
+	return q
+}
+
+func (q *float16Vector) dot(query []float32) float64 {
+	if len(query) != len(q.values) {
+		return 0
+	}
+	var sum float64
+	for i, h := range q.values {
+		sum += float64(query[i]) * float64(float16Table[h])
+	}
+	return sum
+}
+
+func (q *float16Vector) norm() float64 {
+	return q.length
+}
+
+func (q *float16Vector) decode() []float32 {
+	v := make([]float32, len(q.values))
+	for i, h := range q.values {
+		v[i] = float16Table[h]
+	}
+	return v
+}
+
+// float32ToFloat16 converts with round-to-nearest-even, flushing values
+// too small for a half subnormal to zero and saturating to infinity
+func float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp == 0xff: // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp-127 > 15: // too large
+		return sign | 0x7c00
+	case exp-127 >= -14: // normal
+		half := uint32(exp-127+15)<<10 | mant>>13
+		// Round to nearest even on the dropped 13 bits; a carry into the
+		// exponent correctly produces the next power of two (or infinity)
+		rest := mant & 0x1fff
+		if rest > 0x1000 || (rest == 0x1000 && half&1 == 1) {
+			half++
+		}
+		return sign | uint16(half)
+	case exp-127 >= -25: // subnormal
+		mant |= 0x800000
+		shift := uint32(-(exp - 127) - 14 + 13)
+		half := mant >> shift
+		rest := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rest > halfway || (rest == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | uint16(half)
+	}
+	return sign
+}
+
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch {
+	case exp == 0x1f: // Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case exp == 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Subnormal: value is mant * 2^-24
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	}
+	return math.Float32frombits(sign | (exp-15+127)<<23 | mant<<13)
+}
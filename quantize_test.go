@@ -0,0 +1,188 @@
+package brain
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFloat16RoundTrip(t *testing.T) {
+	tests := []struct {
+		in       float32
+		expected float32
+	}{
+		{0, 0},
+		{1, 1},
+		{-2.5, -2.5},
+		{0.1, 0.099975586},
+		{65504, 65504}, // largest half
+		{1e6, float32(math.Inf(1))},
+		{6e-8, 5.9604645e-8}, // smallest half subnormal
+		{1e-9, 0},
+	}
+
+	for _, tt := range tests {
+		if got := float16ToFloat32(float32ToFloat16(tt.in)); got != tt.expected {
+			t.Errorf("float16 round trip of %v = %v, want %v", tt.in, got, tt.expected)
+		}
+	}
+}
+
+func TestQuantizedCosine(t *testing.T) {
+	a := []float32{0.3, -0.7, 0.2, 0.5}
+	b := []float32{0.1, -0.6, 0.4, 0.4}
+	exact := cosineSimilarity(a, b)
+
+	for _, precision := range []Precision{PrecisionFloat16, PrecisionInt8} {
+		got := quantizedCosine(a, vectorNorm(a), quantize(b, precision))
+		if diff := got - exact; diff < -0.01 || diff > 0.01 {
+			t.Errorf("%s cosine = %v, want %v (±0.01)", precision, got, exact)
+		}
+	}
+}
+
+func TestQuantizedDecode(t *testing.T) {
+	v := []float32{0.3, -0.7, 0.2, 0.5}
+	for _, precision := range []Precision{PrecisionFloat16, PrecisionInt8} {
+		decoded := quantize(v, precision).decode()
+		if similarity := cosineSimilarity(v, decoded); similarity < 0.999 {
+			t.Errorf("%s decode = %v, cosine to original %v", precision, decoded, similarity)
+		}
+	}
+}
+
+func TestQuantizedVectorStoreRecall(t *testing.T) {
+	tests := []struct {
+		opts      VectorStoreOptions
+		minRecall float64
+	}{
+		{VectorStoreOptions{Precision: PrecisionFloat16}, 0.98},
+		{VectorStoreOptions{Precision: PrecisionInt8}, 0.85},
+		{VectorStoreOptions{Precision: PrecisionInt8, Rerank: 50}, 0.98},
+	}
+
+	corpus, queries := randomEmbeddings(2000, 50, 256)
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s rerank=%d", tt.opts.Precision, tt.opts.Rerank), func(t *testing.T) {
+			if recall := measureRecall(corpus, queries, tt.opts, 10); recall < tt.minRecall {
+				t.Errorf("recall@10 = %.3f, want at least %.2f", recall, tt.minRecall)
+			}
+		})
+	}
+}
+
+func TestQuantizedVectorStoreReleasesFloats(t *testing.T) {
+	store, _ := NewSimpleVectorStore("", VectorStoreOptions{Precision: PrecisionInt8})
+	note := &Note{ID: "n1", Embedding: []float32{1, 0, 0}}
+	store.Add(note)
+
+	if note.Embedding != nil {
+		t.Error("Expected float32 embedding to be released without re-ranking")
+	}
+
+	results, _ := store.Search([]float32{1, 0, 0}, 1, nil)
+	if len(results) != 1 || results[0].Similarity < 0.99 {
+		t.Errorf("Expected the compressed note to match, got %+v", results)
+	}
+}
+
+// BenchmarkSearchPrecision compares search speed and recall@10 of the
+// quantized stores against exact float32 cosineSimilarity
+func BenchmarkSearchPrecision(b *testing.B) {
+	corpus, queries := randomEmbeddings(5000, 20, 1536)
+
+	for _, opts := range []VectorStoreOptions{
+		{Precision: PrecisionFloat32},
+		{Precision: PrecisionFloat16},
+		{Precision: PrecisionInt8},
+		{Precision: PrecisionInt8, Rerank: 50},
+	} {
+		b.Run(fmt.Sprintf("%s/rerank=%d", opts.Precision, opts.Rerank), func(b *testing.B) {
+			store := buildStore(corpus, opts)
+			recall := measureRecall(corpus, queries, opts, 10)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				store.Search(queries[i%len(queries)], 10, nil)
+			}
+			b.ReportMetric(recall, "recall@10")
+		})
+	}
+}
+
+// randomEmbeddings returns normalized vectors grouped loosely around a
+// few topics, plus queries drawn the same way
+func randomEmbeddings(n, queries, dims int) ([][]float32, [][]float32) {
+	rng := rand.New(rand.NewSource(42))
+
+	topics := make([][]float32, 20)
+	for i := range topics {
+		topics[i] = randomVector(rng, dims, nil, 0)
+	}
+
+	corpus := make([][]float32, n)
+	for i := range corpus {
+		corpus[i] = randomVector(rng, dims, topics[rng.Intn(len(topics))], 0.7)
+	}
+	qs := make([][]float32, queries)
+	for i := range qs {
+		qs[i] = randomVector(rng, dims, topics[rng.Intn(len(topics))], 0.7)
+	}
+
+	return corpus, qs
+}
+
+func randomVector(rng *rand.Rand, dims int, center []float32, noise float64) []float32 {
+	v := make([]float32, dims)
+	var sum float64
+	for i := range v {
+		x := rng.NormFloat64()
+		if center != nil {
+			x = float64(center[i]) + noise*x/math.Sqrt(float64(dims))
+		}
+		v[i] = float32(x)
+		sum += x * x
+	}
+	for i := range v {
+		v[i] /= float32(math.Sqrt(sum))
+	}
+	return v
+}
+
+func buildStore(corpus [][]float32, opts VectorStoreOptions) *SimpleVectorStore {
+	store, _ := NewSimpleVectorStore("", opts)
+	for i, embedding := range corpus {
+		// Copy, since the store may release the float32 slice
+		e := make([]float32, len(embedding))
+		copy(e, embedding)
+		store.Add(&Note{ID: fmt.Sprint(i), Embedding: e})
+	}
+	return store
+}
+
+// measureRecall returns the fraction of the exact top k that the store
+// with the given options also returns in its top k
+func measureRecall(corpus, queries [][]float32, opts VectorStoreOptions, k int) float64 {
+	exact := buildStore(corpus, VectorStoreOptions{Precision: PrecisionFloat32})
+	store := buildStore(corpus, opts)
+
+	found, total := 0, 0
+	for _, query := range queries {
+		want := make(map[string]bool)
+		results, _ := exact.Search(query, k, nil)
+		for _, r := range results {
+			want[r.Note.ID] = true
+		}
+
+		results, _ = store.Search(query, k, nil)
+		for _, r := range results {
+			if want[r.Note.ID] {
+				found++
+			}
+		}
+		total += k
+	}
+
+	return float64(found) / float64(total)
+}
@@ -0,0 +1,220 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// queryDateLayout is the date format accepted by after: and before:
+const queryDateLayout = "2006-01-02"
+
+// Query is a parsed search query such as
+//
+//	tag:go project:myapp after:2026-01-01 -tag:draft "exact phrase" free text
+//
+// Free text and phrases are ranked; everything else filters. Repeated tag:
+// or project: filters match any of their values, negated ones exclude
+// notes with any of theirs, and all kinds of filter must hold together.
+// Tag filters are hierarchical: tag:lang/go matches lang/go/concurrency.
+type Query struct {
+	Text string // free text, ranked semantically and by keyword
+
+	Phrases        []string // must appear in the content (case-insensitive)
+	ExcludePhrases []string // must not appear in the content
+
+	Tags        []string // note has at least one of these tags
+	AllTags     []string // note has every one of these tags
+	ExcludeTags []string // note has none of these tags
+
+	Projects        []string // note belongs to one of these projects
+	ExcludeProjects []string // note belongs to none of these projects
+
+	After  time.Time // note was added on or after this day
+	Before time.Time // note was added before this day
+}
+
+// ParseQuery parses the search query syntax. Unknown field prefixes such
+// as "http:" are treated as free text.
+func ParseQuery(input string) (*Query, error) {
+	q := &Query{}
+	var text []string
+
+	for _, tok := range scanQuery(input) {
+		if tok.field == "" {
+			switch {
+			case tok.negated:
+				q.ExcludePhrases = append(q.ExcludePhrases, tok.value)
+			case tok.quoted:
+				q.Phrases = append(q.Phrases, tok.value)
+			default:
+				text = append(text, tok.value)
+			}
+			continue
+		}
+
+		if tok.value == "" {
+			return nil, fmt.Errorf("%s: needs a value", tok.field)
+		}
+
+		switch tok.field {
+		case "tag":
+			if tok.negated {
+				q.ExcludeTags = append(q.ExcludeTags, tok.value)
+			} else {
+				q.Tags = append(q.Tags, tok.value)
+			}
+		case "project":
+			if tok.negated {
+				q.ExcludeProjects = append(q.ExcludeProjects, tok.value)
+			} else {
+				q.Projects = append(q.Projects, tok.value)
+			}
+		case "after", "before":
+			if tok.negated {
+				return nil, fmt.Errorf("-%s: can't be negated", tok.field)
+			}
+			date, err := time.ParseInLocation(queryDateLayout, tok.value, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%s is not a date (use YYYY-MM-DD)", tok.field, tok.value)
+			}
+			if tok.field == "after" {
+				q.After = date
+			} else {
+				q.Before = date
+			}
+		}
+	}
+
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// SearchText is what gets ranked: the free text plus any quoted phrases
+func (q *Query) SearchText() string {
+	parts := append([]string{}, q.Phrases...)
+	if q.Text != "" {
+		parts = append(parts, q.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Matches reports whether the note passes every filter in the query
+func (q *Query) Matches(note *Note) bool {
+	if !q.TagFilter().Matches(note.Tags) {
+		return false
+	}
+
+	if len(q.Projects) > 0 && !containsFold(q.Projects, note.Project) {
+		return false
+	}
+	if len(q.ExcludeProjects) > 0 && containsFold(q.ExcludeProjects, note.Project) {
+		return false
+	}
+
+	if !q.After.IsZero() && note.Timestamp.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && !note.Timestamp.Before(q.Before) {
+		return false
+	}
+
+	content := strings.ToLower(note.Content)
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(content, strings.ToLower(phrase)) {
+			return false
+		}
+	}
+	for _, phrase := range q.ExcludePhrases {
+		if strings.Contains(content, strings.ToLower(phrase)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TagFilter returns the query's tag filters
+func (q *Query) TagFilter() TagFilter {
+	return TagFilter{Any: q.Tags, All: q.AllTags, Exclude: q.ExcludeTags}
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryToken is one whitespace-separated piece of a query
+type queryToken struct {
+	field   string // known field name, empty for text
+	value   string
+	negated bool
+	quoted  bool
+}
+
+// queryFields are the prefixes recognised as filters
+var queryFields = map[string]bool{
+	"tag":     true,
+	"project": true,
+	"after":   true,
+	"before":  true,
+}
+
+// scanQuery splits a query into tokens, honouring double quotes both for
+// bare phrases and for field values like project:"my app"
+func scanQuery(input string) []queryToken {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var tok queryToken
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			tok.negated = true
+			i++
+		}
+
+		// A field prefix is letters followed by a colon
+		j := i
+		for j < len(runes) && unicode.IsLetter(runes[j]) {
+			j++
+		}
+		if j < len(runes) && runes[j] == ':' && queryFields[strings.ToLower(string(runes[i:j]))] {
+			tok.field = strings.ToLower(string(runes[i:j]))
+			i = j + 1
+		}
+
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			tok.value = string(runes[i+1 : end])
+			tok.quoted = true
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			tok.value = string(runes[i:end])
+			i = end
+		}
+
+		if tok.value == "" && tok.field == "" {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens
+}
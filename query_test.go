@@ -0,0 +1,149 @@
+package brain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *Query
+		wantErr  bool
+	}{
+		{
+			name:     "free text only",
+			input:    "making APIs faster",
+			expected: &Query{Text: "making APIs faster"},
+		},
+		{
+			name:  "filters, phrase and text",
+			input: `tag:go project:myapp -tag:draft "exact phrase" free text`,
+			expected: &Query{
+				Text:        "free text",
+				Phrases:     []string{"exact phrase"},
+				Tags:        []string{"go"},
+				ExcludeTags: []string{"draft"},
+				Projects:    []string{"myapp"},
+			},
+		},
+		{
+			name:  "dates",
+			input: "after:2026-01-01 before:2026-06-01 outage",
+			expected: &Query{
+				Text:   "outage",
+				After:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local),
+				Before: time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local),
+			},
+		},
+		{
+			name:  "quoted field value and negated phrase",
+			input: `project:"my app" -"wont fix" -legacy`,
+			expected: &Query{
+				Projects:       []string{"my app"},
+				ExcludePhrases: []string{"wont fix", "legacy"},
+			},
+		},
+		{
+			name:     "unknown prefix is text",
+			input:    "see https://example.com",
+			expected: &Query{Text: "see https://example.com"},
+		},
+		{
+			name:    "bad date",
+			input:   "after:yesterday",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			input:   "tag: go",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseQuery(%q) expected an error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(q, tt.expected) {
+				t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.input, q, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	note := &Note{
+		Content:   "Connection pool exhausted during the payments outage",
+		Tags:      []string{"go", "incident"},
+		Project:   "payments",
+		Timestamp: time.Date(2026, 3, 15, 10, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"tag:go", true},
+		{"tag:rust tag:incident", true},
+		{"tag:rust", false},
+		{"-tag:incident", false},
+		{"project:payments", true},
+		{"-project:payments", false},
+		{"after:2026-03-01 before:2026-04-01", true},
+		{"after:2026-03-16", false},
+		{"before:2026-03-15", false},
+		{`"connection pool"`, true},
+		{`"thread pool"`, false},
+		{`-"outage"`, false},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+		}
+		if got := q.Matches(note); got != tt.expected {
+			t.Errorf("Query %q matches = %v, want %v", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestSearchPlainText(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	note := &Note{Content: "Run the tests with -race? We decide yes, always with -race in CI", Timestamp: time.Now()}
+	if err := b.AddNote(note); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+
+	// Questions are not query syntax: no exclusions, dates or filters
+	questions := []string{
+		"How do I run tests with -race?",
+		"what did we decide before:friday",
+		"which tests run in project:ci?",
+	}
+	for _, question := range questions {
+		results, err := b.SearchWithOptions(question, SearchOptions{Limit: 5, Mode: SearchModeKeyword, PlainText: true})
+		if err != nil {
+			t.Fatalf("Search for %q failed: %v", question, err)
+		}
+		if len(results) != 1 || results[0].Note != note {
+			t.Errorf("Expected the note for %q, got %d results", question, len(results))
+		}
+	}
+
+	// The same text as a query excludes the note
+	if results, _ := b.SearchWithOptions("How do I run tests with -race?", SearchOptions{Limit: 5, Mode: SearchModeKeyword}); len(results) != 0 {
+		t.Errorf("Expected -race? to exclude the note as query syntax, got %d results", len(results))
+	}
+}
@@ -0,0 +1,92 @@
+package brain
+
+import (
+	"math"
+	"time"
+)
+
+// usageSaturation is the access count at which the usage boost reaches
+// half its maximum; it approaches the maximum for heavily used notes
+const usageSaturation = 5
+
+// RankingOptions tunes how relevance is adjusted after a note has been
+// scored against the query. Each boost multiplies the score, so a note
+// that doesn't match the query stays near the bottom however new, popular
+// or pinned it is.
+type RankingOptions struct {
+	RecencyWeight   float64 `yaml:"recency_weight"`    // boost for a note added today, e.g. 0.1 = +10%
+	RecencyHalfLife int     `yaml:"recency_half_life"` // days after which the recency boost halves
+	UsageWeight     float64 `yaml:"usage_weight"`      // maximum boost for frequently shown notes
+	PinnedBoost     float64 `yaml:"pinned_boost"`      // boost for pinned notes
+}
+
+// DefaultRankingOptions are mild enough that relevance still dominates
+func DefaultRankingOptions() RankingOptions {
+	return RankingOptions{
+		RecencyWeight:   0.1,
+		RecencyHalfLife: 30,
+		UsageWeight:     0.1,
+		PinnedBoost:     0.2,
+	}
+}
+
+// rank applies the ranking boosts to results scored against a query,
+// records them in each breakdown and re-sorts
+func (o RankingOptions) rank(results []SearchResult, now time.Time) {
+	for i := range results {
+		r := &results[i]
+		base := r.Similarity
+
+		r.Score.RecencyBoost = base * o.recencyFactor(r.Note, now)
+		r.Score.UsageBoost = base * o.usageFactor(r.Note)
+		if r.Note.Pinned {
+			r.Score.PinnedBoost = base * o.PinnedBoost
+		}
+
+		r.Similarity = math.Min(1, base+r.Score.RecencyBoost+r.Score.UsageBoost+r.Score.PinnedBoost)
+	}
+	sortResults(results)
+}
+
+// recencyFactor decays exponentially with the note's age
+func (o RankingOptions) recencyFactor(note *Note, now time.Time) float64 {
+	if o.RecencyWeight == 0 || o.RecencyHalfLife <= 0 {
+		return 0
+	}
+
+	ageDays := now.Sub(note.Timestamp).Hours() / 24
+	if ageDays < 0 {
+		ageDays = 0
+	}
+	return o.RecencyWeight * math.Pow(0.5, ageDays/float64(o.RecencyHalfLife))
+}
+
+// usageFactor grows with the number of times the note was shown and
+// levels off, so a few popular notes can't crowd out everything else
+func (o RankingOptions) usageFactor(note *Note) float64 {
+	if o.UsageWeight == 0 || note.AccessCount == 0 {
+		return 0
+	}
+
+	count := float64(note.AccessCount)
+	return o.UsageWeight * count / (count + usageSaturation)
+}
+
+// RecordAccess counts the notes as shown to the user, which feeds the
+// usage boost
+func (b *Brain) RecordAccess(notes ...*Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	for _, note := range notes {
+		note.AccessCount++
+	}
+	return b.saveNotes()
+}
+
+// SetPinned pins or unpins a note. Pinned notes rank higher in searches.
+func (b *Brain) SetPinned(note *Note, pinned bool) error {
+	note.Pinned = pinned
+	return b.saveNotes()
+}
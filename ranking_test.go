@@ -0,0 +1,96 @@
+package brain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRankingBoosts(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	opts := RankingOptions{RecencyWeight: 0.1, RecencyHalfLife: 30, UsageWeight: 0.1, PinnedBoost: 0.2}
+
+	tests := []struct {
+		name     string
+		note     *Note
+		expected float64
+	}{
+		{"old note", &Note{Timestamp: now.AddDate(-5, 0, 0)}, 0.5},
+		{"new note", &Note{Timestamp: now}, 0.55},
+		{"one half-life old", &Note{Timestamp: now.AddDate(0, 0, -30)}, 0.525},
+		{"often shown", &Note{Timestamp: now.AddDate(-5, 0, 0), AccessCount: 5}, 0.525},
+		{"pinned", &Note{Timestamp: now.AddDate(-5, 0, 0), Pinned: true}, 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []SearchResult{{Note: tt.note, Similarity: 0.5}}
+			opts.rank(results, now)
+			if math.Abs(results[0].Similarity-tt.expected) > 1e-3 {
+				t.Errorf("Ranked score = %.4f, want %.4f (breakdown %+v)", results[0].Similarity, tt.expected, results[0].Score)
+			}
+		})
+	}
+}
+
+func TestRankingPrefersRecentAndPinned(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	old := &Note{Content: "Deploy checklist: run migrations first", Timestamp: time.Now().AddDate(-2, 0, 0)}
+	recent := &Note{Content: "Deploy checklist: run migrations first", Timestamp: time.Now()}
+	for _, note := range []*Note{old, recent} {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	results, err := b.SearchWithOptions("deploy checklist", SearchOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Note != recent {
+		t.Fatalf("Expected the recent note first, got %+v", results)
+	}
+
+	if err := b.SetPinned(old, true); err != nil {
+		t.Fatalf("SetPinned failed: %v", err)
+	}
+	results, _ = b.SearchWithOptions("deploy checklist", SearchOptions{Limit: 2})
+	if results[0].Note != old {
+		t.Errorf("Expected the pinned note first, got %q", results[0].Note.ID)
+	}
+
+	// Pins and access counts survive a reload
+	if err := b.RecordAccess(old, old); err != nil {
+		t.Fatalf("RecordAccess failed: %v", err)
+	}
+	if err := b.loadNotes(); err != nil {
+		t.Fatalf("Failed to reload notes: %v", err)
+	}
+	reloaded, err := b.FindNote(old.ID)
+	if err != nil {
+		t.Fatalf("FindNote failed: %v", err)
+	}
+	if !reloaded.Pinned || reloaded.AccessCount != 2 {
+		t.Errorf("Expected pinned note with 2 accesses, got pinned=%v accesses=%d", reloaded.Pinned, reloaded.AccessCount)
+	}
+}
+
+func TestFindNote(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, id := range []string{"abc123", "abd456"} {
+		if err := b.AddNote(&Note{ID: id, Content: "note " + id, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	if note, err := b.FindNote("abc"); err != nil || note.ID != "abc123" {
+		t.Errorf("FindNote(abc) = %v, %v", note, err)
+	}
+	if _, err := b.FindNote("ab"); err == nil {
+		t.Error("Expected an error for an ambiguous prefix")
+	}
+	if _, err := b.FindNote("zzz"); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound, got %v", err)
+	}
+}
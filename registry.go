@@ -0,0 +1,98 @@
+package brain
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// EmbedderSpec configures a named embedder in config.yaml
+type EmbedderSpec struct {
+	Type    string            `yaml:"type"`    // registered embedder type: openai, local, exec
+	Model   string            `yaml:"model"`   // model name, for openai
+	Command string            `yaml:"command"` // executable, for exec
+	Args    []string          `yaml:"args"`    // arguments, for exec
+	Env     map[string]string `yaml:"env"`     // extra environment, for exec
+}
+
+// EmbedderFactory builds an embedder from its config
+type EmbedderFactory func(spec EmbedderSpec) (Embedder, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]EmbedderFactory{}
+)
+
+// RegisterEmbedder makes an embedder type available by name.
+// Registering the same name twice replaces the earlier factory.
+func RegisterEmbedder(name string, factory EmbedderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[name] = factory
+}
+
+// RegisteredEmbedders returns the names of all embedder types, sorted
+func RegisteredEmbedders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterEmbedder("openai", func(spec EmbedderSpec) (Embedder, error) {
+		remote, err := NewOpenAIEmbedder()
+		if err != nil {
+			return nil, err
+		}
+		if spec.Model != "" {
+			remote.model = spec.Model
+		}
+		return NewResilientEmbedder(remote, DefaultResilienceOptions()), nil
+	})
+	RegisterEmbedder("local", func(spec EmbedderSpec) (Embedder, error) {
+		return NewLocalEmbedder(), nil
+	})
+	RegisterEmbedder("exec", func(spec EmbedderSpec) (Embedder, error) {
+		return NewExecEmbedder(spec.Command, spec.Args, spec.Env)
+	})
+}
+
+// newEmbedder builds the embedder selected in the config. The name refers
+// either to an entry under embedders: or directly to a registered type.
+// Without a name we use OpenAI if a key is set and the local embedder otherwise.
+func newEmbedder(config *Config) (Embedder, error) {
+	name := config.Embedder
+	if name == "" {
+		if remote, err := NewOpenAIEmbedder(); err == nil {
+			// Retry transient failures and stay under the provider's rate limits
+			return NewResilientEmbedder(remote, DefaultResilienceOptions()), nil
+		}
+		// Fall back to local embedder if OpenAI isn't configured
+		return NewLocalEmbedder(), nil
+	}
+
+	spec, ok := config.Embedders[name]
+	if !ok {
+		spec = EmbedderSpec{Type: name}
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[spec.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown embedder %q (available: %v)", spec.Type, RegisteredEmbedders())
+	}
+
+	embedder, err := factory(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedder %q: %w", name, err)
+	}
+	return embedder, nil
+}
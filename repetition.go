@@ -0,0 +1,209 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SM-2 parameters, as in SuperMemo 2
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+
+	// MaxQuality is the best recall grade; below PassQuality the note
+	// was forgotten and starts over
+	MaxQuality  = 5
+	PassQuality = 3
+)
+
+const reviewQuestionPrompt = `You help someone review their own notes with spaced repetition.
+Write one short question that tests recall of the most important fact or idea in the note below, without giving the answer away.
+Reply with only the question.`
+
+// ReviewState is a note's spaced repetition schedule, SM-2 style. It's nil
+// until the note is reviewed for the first time.
+type ReviewState struct {
+	Ease        float64   `json:"ease"`
+	Interval    int       `json:"interval"`    // days until the next review
+	Repetitions int       `json:"repetitions"` // successful reviews in a row
+	Due         time.Time `json:"due"`
+	Reviewed    time.Time `json:"reviewed"` // the last review
+	Reviews     int       `json:"reviews"`
+	Lapses      int       `json:"lapses,omitempty"` // reviews graded below PassQuality
+}
+
+// schedule updates the state after a review graded quality (0-5): a pass
+// lengthens the interval by the ease, a lapse starts over at one day, and
+// the ease moves with how easy the recall was
+func (s *ReviewState) schedule(quality int, now time.Time) {
+	if quality >= PassQuality {
+		switch s.Repetitions {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.Ease))
+		}
+		s.Repetitions++
+	} else {
+		s.Repetitions = 0
+		s.Interval = 1
+		s.Lapses++
+	}
+
+	miss := float64(MaxQuality - quality)
+	s.Ease = math.Max(minEase, s.Ease+0.1-miss*(0.08+miss*0.02))
+	s.Reviews++
+	s.Reviewed = now
+	s.Due = now.AddDate(0, 0, s.Interval)
+}
+
+// ReviewOptions selects the notes for a review session
+type ReviewOptions struct {
+	Limit  int       // most notes to review, 0 for all that are due
+	New    int       // most notes reviewed for the first time
+	Filter TagFilter // only notes passing the filter
+}
+
+// ReviewQueue returns the notes to review now: those due, most overdue
+// first, then up to opts.New notes never reviewed, pinned and often used
+// ones first
+func (b *Brain) ReviewQueue(now time.Time, opts ReviewOptions) []*Note {
+	var due, fresh []*Note
+	for _, note := range b.vectorStore.GetAllNotes() {
+		if !opts.Filter.Matches(note.Tags) {
+			continue
+		}
+		if note.Review == nil {
+			fresh = append(fresh, note)
+		} else if !note.Review.Due.After(now) {
+			due = append(due, note)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Review.Due.Before(due[j].Review.Due)
+	})
+	sort.SliceStable(fresh, func(i, j int) bool {
+		if fresh[i].Pinned != fresh[j].Pinned {
+			return fresh[i].Pinned
+		}
+		if fresh[i].AccessCount != fresh[j].AccessCount {
+			return fresh[i].AccessCount > fresh[j].AccessCount
+		}
+		return fresh[i].Timestamp.Before(fresh[j].Timestamp)
+	})
+	if len(fresh) > opts.New {
+		fresh = fresh[:opts.New]
+	}
+
+	queue := append(due, fresh...)
+	if opts.Limit > 0 && len(queue) > opts.Limit {
+		queue = queue[:opts.Limit]
+	}
+	return queue
+}
+
+// RecordReview schedules a note's next review from how well it was
+// recalled, from 0 (forgotten) to 5 (perfect), and saves it
+func (b *Brain) RecordReview(note *Note, quality int) error {
+	if quality < 0 || quality > MaxQuality {
+		return fmt.Errorf("recall quality must be between 0 and %d, got %d", MaxQuality, quality)
+	}
+
+	if note.Review == nil {
+		note.Review = &ReviewState{Ease: initialEase}
+	}
+	note.Review.schedule(quality, time.Now())
+	return b.saveNotes()
+}
+
+// ReviewQuestion asks the chat model for a question that tests recall of
+// the note
+func (b *Brain) ReviewQuestion(ctx context.Context, note *Note) (string, error) {
+	if b.llm == nil {
+		return "", ErrNoLLM
+	}
+
+	completion, err := b.complete(ctx, []Message{
+		{Role: "system", Content: reviewQuestionPrompt},
+		{Role: "user", Content: truncateRunes(note.Content, b.config.LLM.ContextTokens*charsPerToken)},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(completion.Content), nil
+}
+
+// ReviewStats summarises spaced repetition across all notes
+type ReviewStats struct {
+	Notes         int // all notes
+	InReview      int // notes reviewed at least once
+	DueNow        int
+	DueWeek       int       // due within the next seven days, including now
+	NextDue       time.Time // the earliest review not due yet
+	ReviewedToday int
+	Reviews       int
+	Lapses        int
+	MeanEase      float64
+	Hardest       []*Note // up to three notes with the lowest ease, lowest first
+}
+
+// Retention is the share of reviews that passed
+func (s ReviewStats) Retention() float64 {
+	if s.Reviews == 0 {
+		return 0
+	}
+	return 1 - float64(s.Lapses)/float64(s.Reviews)
+}
+
+// ReviewStats counts what's due and how reviews have gone
+func (b *Brain) ReviewStats(now time.Time) ReviewStats {
+	var stats ReviewStats
+	var reviewed []*Note
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for _, note := range b.vectorStore.GetAllNotes() {
+		stats.Notes++
+		r := note.Review
+		if r == nil {
+			continue
+		}
+
+		reviewed = append(reviewed, note)
+		stats.InReview++
+		stats.Reviews += r.Reviews
+		stats.Lapses += r.Lapses
+		stats.MeanEase += r.Ease
+		if !r.Due.After(now) {
+			stats.DueNow++
+		} else if stats.NextDue.IsZero() || r.Due.Before(stats.NextDue) {
+			stats.NextDue = r.Due
+		}
+		if !r.Due.After(now.AddDate(0, 0, 7)) {
+			stats.DueWeek++
+		}
+		if !r.Reviewed.Before(startOfDay) {
+			stats.ReviewedToday++
+		}
+	}
+	if stats.InReview > 0 {
+		stats.MeanEase /= float64(stats.InReview)
+	}
+
+	sort.SliceStable(reviewed, func(i, j int) bool {
+		return reviewed[i].Review.Ease < reviewed[j].Review.Ease
+	})
+	for _, note := range reviewed {
+		if len(stats.Hardest) == 3 || note.Review.Ease >= initialEase {
+			break
+		}
+		stats.Hardest = append(stats.Hardest, note)
+	}
+	return stats
+}
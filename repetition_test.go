@@ -0,0 +1,127 @@
+package brain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReviewSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	state := &ReviewState{Ease: initialEase}
+
+	// Intervals grow 1, 6, then by the ease; a lapse starts over
+	steps := []struct {
+		quality      int
+		wantInterval int
+		wantEase     float64
+	}{
+		{quality: 4, wantInterval: 1, wantEase: 2.5},
+		{quality: 5, wantInterval: 6, wantEase: 2.6},
+		{quality: 3, wantInterval: 16, wantEase: 2.46},
+		{quality: 1, wantInterval: 1, wantEase: 1.92},
+		{quality: 4, wantInterval: 1, wantEase: 1.92},
+	}
+
+	for i, step := range steps {
+		state.schedule(step.quality, now)
+		if state.Interval != step.wantInterval || !approxEqual(state.Ease, step.wantEase) {
+			t.Errorf("Step %d: expected interval %d and ease %.2f, got %d and %.2f",
+				i, step.wantInterval, step.wantEase, state.Interval, state.Ease)
+		}
+	}
+	if state.Reviews != 5 || state.Lapses != 1 || state.Repetitions != 1 {
+		t.Errorf("Unexpected counts %+v", state)
+	}
+	if !state.Due.Equal(now.AddDate(0, 0, 1)) {
+		t.Errorf("Expected the note due tomorrow, got %v", state.Due)
+	}
+
+	// The ease never drops below the SM-2 minimum
+	for i := 0; i < 10; i++ {
+		state.schedule(0, now)
+	}
+	if state.Ease != minEase {
+		t.Errorf("Expected the minimum ease, got %v", state.Ease)
+	}
+}
+
+func TestReviewQueue(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	now := time.Now()
+
+	overdue := &Note{Content: "Overdue", Tags: []string{"go"}, Review: &ReviewState{Ease: 2.5, Due: now.AddDate(0, 0, -3)}}
+	due := &Note{Content: "Due", Tags: []string{"go"}, Review: &ReviewState{Ease: 2.5, Due: now.Add(-time.Hour)}}
+	later := &Note{Content: "Later", Tags: []string{"go"}, Review: &ReviewState{Ease: 2.5, Due: now.AddDate(0, 0, 2)}}
+	fresh := &Note{Content: "Never reviewed", Tags: []string{"go"}, Timestamp: now.AddDate(0, 0, -1)}
+	pinned := &Note{Content: "Pinned", Tags: []string{"go"}, Pinned: true, Timestamp: now}
+	other := &Note{Content: "Other tag", Tags: []string{"rust"}}
+	for _, note := range []*Note{overdue, due, later, fresh, pinned, other} {
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	opts := ReviewOptions{New: 1, Filter: TagFilter{Any: []string{"go"}}}
+	queue := b.ReviewQueue(now, opts)
+	if len(queue) != 3 || queue[0] != overdue || queue[1] != due || queue[2] != pinned {
+		t.Fatalf("Expected overdue, due and the pinned new note, got %v", contents(queue))
+	}
+
+	opts.Limit = 1
+	if queue := b.ReviewQueue(now, opts); len(queue) != 1 || queue[0] != overdue {
+		t.Errorf("Expected only the most overdue note, got %v", contents(queue))
+	}
+
+	if err := b.RecordReview(fresh, 6); err == nil {
+		t.Error("Expected an error for a quality above 5")
+	}
+	if err := b.RecordReview(fresh, 4); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+	if fresh.Review == nil || fresh.Review.Interval != 1 {
+		t.Errorf("Expected the note scheduled for tomorrow, got %+v", fresh.Review)
+	}
+
+	stats := b.ReviewStats(now)
+	if stats.Notes != 6 || stats.InReview != 4 || stats.DueNow != 2 || stats.DueWeek != 4 || stats.ReviewedToday != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if !stats.NextDue.Equal(fresh.Review.Due) {
+		t.Errorf("Expected the next due review to be tomorrow's, got %v", stats.NextDue)
+	}
+}
+
+func TestReviewQuestion(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	note := &Note{Content: "Postgres pool size should match the cores"}
+
+	if _, err := b.ReviewQuestion(context.Background(), note); err != ErrNoLLM {
+		t.Fatalf("Expected ErrNoLLM without a model, got %v", err)
+	}
+
+	server, last := newChatStub(t, " How big should a Postgres pool be?\n")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+	question, err := b.ReviewQuestion(context.Background(), note)
+	if err != nil {
+		t.Fatalf("ReviewQuestion failed: %v", err)
+	}
+	if question != "How big should a Postgres pool be?" {
+		t.Errorf("Unexpected question %q", question)
+	}
+	if last.Messages[1].Content != note.Content {
+		t.Errorf("Expected the note as the prompt, got %q", last.Messages[1].Content)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
+
+func contents(notes []*Note) []string {
+	var result []string
+	for _, note := range notes {
+		result = append(result, note.Content)
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarise recent notes as Markdown",
+	Long: `Write a Markdown digest of the notes added recently, grouped by tag or
+project. When a chat model is configured it also writes an overview
+paragraph; without one, or with --no-llm, the notes are just grouped and
+listed.
+
+--since takes a duration such as 7d, 2w or 12h, or a date like 2026-10-01.
+
+Examples:
+  brain digest
+  brain digest --since 14d --project payments
+  brain digest --saved payments-incidents --group-by project -o sync.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		project, _ := cmd.Flags().GetString("project")
+		saved, _ := cmd.Flags().GetString("saved")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		noLLM, _ := cmd.Flags().GetBool("no-llm")
+		output, _ := cmd.Flags().GetString("output")
+
+		since, err := brain.ParseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		digest, err := b.Digest(cmd.Context(), brain.DigestOptions{
+			Since:    since,
+			Project:  project,
+			Saved:    saved,
+			GroupBy:  groupBy,
+			Overview: !noLLM,
+		})
+		if digest == nil {
+			return err
+		}
+		if err != nil {
+			// The notes are still worth listing without the overview
+			fmt.Fprintf(os.Stderr, "⚠ %v\n", explainError(err))
+		}
+
+		if len(digest.Notes) == 0 {
+			fmt.Printf("No notes added since %s\n", since.Format("2006-01-02 15:04"))
+			return nil
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if _, err := io.WriteString(w, digest.Markdown()); err != nil {
+			return fmt.Errorf("failed to write the digest: %w", err)
+		}
+		if output != "" {
+			fmt.Printf("✓ Wrote a digest of %d note(s) to %s\n", len(digest.Notes), output)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+	digestCmd.Flags().String("since", "7d", "Include notes added since this long ago or this date")
+	digestCmd.Flags().StringP("project", "p", "", "Only notes from this project")
+	digestCmd.Flags().String("saved", "", "Only notes matched by this saved search")
+	digestCmd.Flags().String("group-by", brain.GroupByTag, "Group notes by tag or project")
+	digestCmd.Flags().Bool("no-llm", false, "Don't write an overview with the chat model")
+	digestCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+}
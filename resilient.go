@@ -0,0 +1,277 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Errors returned by remote embedding and chat APIs, so callers can tell
+// a bad key apart from a provider that's just busy. APIError.Service says
+// which API failed.
+var (
+	ErrRateLimited   = errors.New("provider rate limit exceeded")
+	ErrAuth          = errors.New("provider rejected the API key")
+	ErrQuotaExceeded = errors.New("provider quota exceeded")
+)
+
+// APIError is a non-200 response from a remote embedding or chat provider
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+	Service    string // which API failed, "embedding" when empty
+}
+
+func (e *APIError) Error() string {
+	service := e.Service
+	if service == "" {
+		service = "embedding"
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("%s API returned status %d", service, e.StatusCode)
+	}
+	return fmt.Sprintf("%s API returned status %d: %s", service, e.StatusCode, e.Message)
+}
+
+// Unwrap maps the response onto one of the sentinel errors above
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrAuth
+	case e.Code == "insufficient_quota":
+		// OpenAI reports an exhausted quota as a 429 too, but retrying won't help
+		return ErrQuotaExceeded
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Retryable reports whether the same request might succeed later
+func (e *APIError) Retryable() bool {
+	if errors.Is(e, ErrQuotaExceeded) {
+		return false
+	}
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode >= 500
+}
+
+// parseRetryAfter understands both forms of the Retry-After header:
+// a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ResilienceOptions controls how a ResilientEmbedder talks to its provider
+type ResilienceOptions struct {
+	MaxRetries     int           // retries after the first attempt
+	BaseDelay      time.Duration // backoff before the first retry
+	MaxDelay       time.Duration // cap for exponential backoff
+	RequestTimeout time.Duration // per-attempt timeout
+	RatePerSecond  float64       // sustained request rate, 0 = unlimited
+	Burst          int           // requests allowed back to back
+}
+
+// DefaultResilienceOptions returns settings that sit comfortably inside
+// OpenAI's lowest paid rate-limit tier
+func DefaultResilienceOptions() ResilienceOptions {
+	return ResilienceOptions{
+		MaxRetries:     4,
+		BaseDelay:      500 * time.Millisecond,
+		MaxDelay:       30 * time.Second,
+		RequestTimeout: 30 * time.Second,
+		RatePerSecond:  5,
+		Burst:          10,
+	}
+}
+
+// ResilientEmbedder wraps a remote embedder with retries, exponential
+// backoff with jitter, rate limiting and per-request timeouts
+type ResilientEmbedder struct {
+	embedder ContextEmbedder
+	opts     ResilienceOptions
+	limiter  *rateLimiter
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func NewResilientEmbedder(embedder ContextEmbedder, opts ResilienceOptions) *ResilientEmbedder {
+	return &ResilientEmbedder{
+		embedder: embedder,
+		opts:     opts,
+		limiter:  newRateLimiter(opts.RatePerSecond, opts.Burst),
+		sleep:    sleepContext,
+	}
+}
+
+func (e *ResilientEmbedder) Embed(text string) ([]float32, error) {
+	return e.EmbedContext(context.Background(), text)
+}
+
+func (e *ResilientEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= e.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := e.sleep(ctx, e.backoff(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := e.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		embedding, err := e.attempt(ctx, text)
+		if err == nil {
+			return embedding, nil
+		}
+		lastErr = err
+
+		// Stop early if the caller gave up, retrying can't help, or the
+		// provider wants a longer wait than we allow between attempts
+		if ctx.Err() != nil || !isRetryable(err) || retryAfter(err) > e.opts.MaxDelay {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", e.opts.MaxRetries+1, lastErr)
+}
+
+func (e *ResilientEmbedder) attempt(ctx context.Context, text string) ([]float32, error) {
+	if e.opts.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.opts.RequestTimeout)
+		defer cancel()
+	}
+	return e.embedder.EmbedContext(ctx, text)
+}
+
+// backoff returns how long to wait before the given retry. A Retry-After
+// from the provider wins; otherwise we use exponential backoff with full jitter.
+func (e *ResilientEmbedder) backoff(attempt int, lastErr error) time.Duration {
+	if wait := retryAfter(lastErr); wait > 0 {
+		return wait
+	}
+
+	delay := e.opts.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > e.opts.MaxDelay {
+		delay = e.opts.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// retryAfter is how long the provider asked us to wait, 0 if it didn't say
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	// Our own per-attempt timeout fired; the caller's context is checked separately
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Connection resets, refused connections, DNS hiccups...
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter is a token bucket refilled at a fixed rate
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
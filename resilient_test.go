@@ -0,0 +1,153 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type flakyEmbedder struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyEmbedder) Embed(text string) ([]float32, error) {
+	return f.EmbedContext(context.Background(), text)
+}
+
+func (f *flakyEmbedder) EmbedContext(ctx context.Context, text string) ([]float32, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return []float32{1, 0, 0}, nil
+}
+
+func newTestResilientEmbedder(inner ContextEmbedder) (*ResilientEmbedder, *[]time.Duration) {
+	var delays []time.Duration
+	e := NewResilientEmbedder(inner, ResilienceOptions{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+	})
+	e.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return e, &delays
+}
+
+func TestResilientEmbedderRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "succeeds after server errors",
+			errs:      []error{&APIError{StatusCode: 503}, &APIError{StatusCode: 500}},
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up when rate limited",
+			errs:      []error{&APIError{StatusCode: 429}, &APIError{StatusCode: 429}, &APIError{StatusCode: 429}, &APIError{StatusCode: 429}},
+			wantCalls: 4,
+			wantErr:   ErrRateLimited,
+		},
+		{
+			name:      "gives up when asked to wait longer than MaxDelay",
+			errs:      []error{&APIError{StatusCode: 429, RetryAfter: time.Hour}},
+			wantCalls: 1,
+			wantErr:   ErrRateLimited,
+		},
+		{
+			name:      "does not retry auth errors",
+			errs:      []error{&APIError{StatusCode: 401}},
+			wantCalls: 1,
+			wantErr:   ErrAuth,
+		},
+		{
+			name:      "does not retry exhausted quota",
+			errs:      []error{&APIError{StatusCode: 429, Code: "insufficient_quota"}},
+			wantCalls: 1,
+			wantErr:   ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &flakyEmbedder{errs: tt.errs}
+			e, _ := newTestResilientEmbedder(inner)
+
+			_, err := e.Embed("test")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Embed() returned error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Embed() error = %v, want %v", err, tt.wantErr)
+			}
+			if inner.calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, inner.calls)
+			}
+		})
+	}
+}
+
+func TestResilientEmbedderHonoursRetryAfter(t *testing.T) {
+	inner := &flakyEmbedder{errs: []error{&APIError{StatusCode: 429, RetryAfter: 7 * time.Millisecond}}}
+	e, delays := newTestResilientEmbedder(inner)
+
+	if _, err := e.Embed("test"); err != nil {
+		t.Fatalf("Embed() returned error: %v", err)
+	}
+
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Millisecond {
+		t.Errorf("Expected a single 7ms delay, got %v", *delays)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestOpenAIEmbedderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": {"message": "Rate limit reached", "code": "rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	e := &OpenAIEmbedder{apiKey: "test", model: openAIEmbeddingModel, url: server.URL, client: server.Client()}
+
+	_, err := e.Embed("test")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*time.Second {
+		t.Errorf("Expected Retry-After of 2s, got %+v", apiErr)
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var retagCmd = &cobra.Command{
+	Use:   "retag",
+	Short: "Suggest tags for untagged notes",
+	Long: `Go through the notes that have no tags and suggest some for each, from
+the tags of the most similar notes. With --llm-tags the chat model is asked
+as well.
+
+Each suggestion can be accepted with Enter, replaced by typing your own
+tags, or skipped with "-". With --auto-tag every suggestion is accepted.
+Without a terminal and without --auto-tag the suggestions are only listed.
+
+Examples:
+  brain retag
+  brain retag --auto-tag
+  brain retag --llm-tags --limit 20`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		auto, _ := cmd.Flags().GetBool("auto-tag")
+		useLLM, _ := cmd.Flags().GetBool("llm-tags")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+		if useLLM && !b.HasLLM() {
+			fmt.Println("⚠ No LLM configured, suggesting tags from similar notes only")
+		}
+
+		notes := b.UntaggedNotes()
+		if len(notes) == 0 {
+			fmt.Println("✓ Every note has tags")
+			return nil
+		}
+		total := len(notes)
+		if limit > 0 && len(notes) > limit {
+			notes = notes[:limit]
+		}
+
+		t := newTagger(b, brain.TagOptions{UseLLM: useLLM}, auto)
+		tagged := 0
+		for _, note := range notes {
+			fmt.Printf("\n[%s] %s  %s\n", note.Timestamp.Format("2006-01-02"), brain.ShortID(note.ID), brain.FirstLine(note.Content))
+			tags := t.suggest(cmd.Context(), note)
+			if len(tags) == 0 {
+				fmt.Println("  No tags to suggest")
+				continue
+			}
+			ok, err := t.apply(note, tags)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if ok {
+				tagged++
+			}
+		}
+
+		fmt.Printf("\n✓ Tagged %d of %d untagged note(s)\n", tagged, total)
+		return nil
+	},
+}
+
+// tagger suggests tags for notes and applies them: straight away with
+// --auto-tag, after asking when stdin is a terminal, and otherwise only
+// prints them
+type tagger struct {
+	b           *brain.Brain
+	opts        brain.TagOptions
+	auto        bool
+	interactive bool
+	in          *bufio.Reader
+}
+
+func newTagger(b *brain.Brain, opts brain.TagOptions, auto bool) *tagger {
+	return &tagger{
+		b:           b,
+		opts:        opts,
+		auto:        auto,
+		interactive: isTerminal(os.Stdin),
+		in:          bufio.NewReader(os.Stdin),
+	}
+}
+
+// suggest returns the suggested tags for a note. A failing LLM is
+// reported and the tags from similar notes are used instead.
+func (t *tagger) suggest(ctx context.Context, note *brain.Note) []string {
+	tags, err := t.b.SuggestTags(ctx, note, t.opts)
+	if err != nil {
+		fmt.Printf("⚠ %v\n", explainError(err))
+	}
+	return tags
+}
+
+// apply adds the suggested tags to a note, or the ones typed instead, and
+// reports whether any were added. It returns io.EOF when the user closed
+// stdin.
+func (t *tagger) apply(note *brain.Note, tags []string) (bool, error) {
+	switch {
+	case t.auto:
+	case t.interactive:
+		fmt.Printf("🏷  Suggested tags: %s\n", strings.Join(tags, ", "))
+		fmt.Print("  Enter to accept, type your own (comma-separated), or - to skip: ")
+		line, err := t.in.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Println()
+			return false, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "-" {
+			return false, nil
+		}
+		if line != "" {
+			tags = splitTags(line)
+		}
+	default:
+		fmt.Printf("💡 Suggested tags: %s (apply them with --auto-tag)\n", strings.Join(tags, ", "))
+		return false, nil
+	}
+
+	if err := t.b.AddTags(note, tags...); err != nil {
+		return false, fmt.Errorf("failed to save tags: %w", err)
+	}
+	fmt.Printf("✓ Tagged: %s\n", strings.Join(tags, ", "))
+	return true, nil
+}
+
+// splitTags reads a comma or space separated list of tags
+func splitTags(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(retagCmd)
+	retagCmd.Flags().Bool("auto-tag", false, "Accept every suggestion without asking")
+	retagCmd.Flags().Bool("llm-tags", false, "Ask the chat model for tags as well")
+	retagCmd.Flags().IntP("limit", "l", 0, "Only go through this many notes, oldest first (0 for all)")
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review notes with spaced repetition",
+	Long: `Go through the notes due for review and grade how well you remembered
+each one, from 0 (forgot it) to 5 (perfect). Notes you remember come back
+after longer and longer intervals; notes you forgot come back tomorrow.
+Each session also brings in a few notes you've never reviewed, pinned
+and often used ones first.
+
+With --questions the chat model turns each note into a question, and the
+note is shown once you press Enter.
+
+Examples:
+  brain review
+  brain review --questions --limit 10
+  brain review --tags postgres --new 0
+  brain review --stats`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, _ := cmd.Flags().GetBool("stats")
+		limit, _ := cmd.Flags().GetInt("limit")
+		newNotes, _ := cmd.Flags().GetInt("new")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		questions, _ := cmd.Flags().GetBool("questions")
+		if limit < 0 || newNotes < 0 {
+			return fmt.Errorf("--limit and --new must not be negative")
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		if stats {
+			printReviewStats(b.ReviewStats(time.Now()))
+			return nil
+		}
+
+		if questions && !b.HasLLM() {
+			return fmt.Errorf("%w: set OPENAI_API_KEY, or configure llm: in ~/.brain/config.yaml, to use --questions", brain.ErrNoLLM)
+		}
+
+		queue := b.ReviewQueue(time.Now(), brain.ReviewOptions{
+			Limit:  limit,
+			New:    newNotes,
+			Filter: brain.TagFilter{Any: tags},
+		})
+		if len(queue) == 0 {
+			fmt.Println("✓ Nothing to review right now")
+			if next := b.ReviewStats(time.Now()).NextDue; !next.IsZero() {
+				fmt.Printf("  The next review is due %s\n", next.Format("2006-01-02 15:04"))
+			}
+			return nil
+		}
+
+		in := bufio.NewReader(os.Stdin)
+		reviewed := 0
+		for i, note := range queue {
+			fmt.Printf("\n📝 %d/%d  [%s] %s  %s\n", i+1, len(queue), note.Timestamp.Format("2006-01-02"), brain.ShortID(note.ID), reviewStatus(note))
+
+			if questions {
+				question, err := b.ReviewQuestion(cmd.Context(), note)
+				if err != nil {
+					fmt.Printf("⚠ %v\n", explainError(err))
+				} else {
+					fmt.Printf("❓ %s\n", question)
+					fmt.Print("  Press Enter to see the note ")
+					if _, err := in.ReadString('\n'); err != nil {
+						fmt.Println()
+						break
+					}
+				}
+			}
+			fmt.Printf("\n%s\n\n", note.Content)
+
+			quality, err := readQuality(in)
+			if err == io.EOF || errors.Is(err, errQuitReview) {
+				break
+			}
+			if errors.Is(err, errSkipReview) {
+				continue
+			}
+
+			if err := b.RecordReview(note, quality); err != nil {
+				return fmt.Errorf("failed to save the review: %w", err)
+			}
+			reviewed++
+			fmt.Printf("✓ Next review in %d day(s)\n", note.Review.Interval)
+		}
+
+		fmt.Printf("\n✓ Reviewed %d note(s)\n", reviewed)
+		return nil
+	},
+}
+
+var (
+	errSkipReview = errors.New("skip")
+	errQuitReview = errors.New("quit")
+)
+
+// readQuality asks for a recall grade until it gets one, or s to skip or
+// q to quit
+func readQuality(in *bufio.Reader) (int, error) {
+	for {
+		fmt.Printf("  How well did you remember it? 0 forgot · %d hard · 4 good · %d easy  (s skip, q quit): ", brain.PassQuality, brain.MaxQuality)
+		line, err := in.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Println()
+			return 0, err
+		}
+
+		switch line = strings.TrimSpace(line); line {
+		case "s":
+			return 0, errSkipReview
+		case "q":
+			return 0, errQuitReview
+		}
+		if quality, err := strconv.Atoi(line); err == nil && quality >= 0 && quality <= brain.MaxQuality {
+			return quality, nil
+		}
+	}
+}
+
+// reviewStatus describes where a note is in its schedule
+func reviewStatus(note *brain.Note) string {
+	if note.Review == nil {
+		return "new"
+	}
+	overdue := int(time.Since(note.Review.Due).Hours() / 24)
+	if overdue < 1 {
+		return "due today"
+	}
+	return fmt.Sprintf("due %d day(s) ago", overdue)
+}
+
+func printReviewStats(stats brain.ReviewStats) {
+	fmt.Println("📊 Review stats")
+	fmt.Printf("  Notes in review:  %d of %d\n", stats.InReview, stats.Notes)
+	fmt.Printf("  Due now:          %d\n", stats.DueNow)
+	fmt.Printf("  Due this week:    %d\n", stats.DueWeek)
+	if !stats.NextDue.IsZero() {
+		fmt.Printf("  Next review:      %s\n", stats.NextDue.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("  Reviewed today:   %d\n", stats.ReviewedToday)
+	if stats.Reviews > 0 {
+		fmt.Printf("  Reviews:          %d, %.0f%% remembered\n", stats.Reviews, stats.Retention()*100)
+		fmt.Printf("  Average ease:     %.2f\n", stats.MeanEase)
+	}
+
+	if len(stats.Hardest) > 0 {
+		fmt.Println("\nHardest to remember:")
+		for _, note := range stats.Hardest {
+			fmt.Printf("  %s  ease %.2f  %s\n", brain.ShortID(note.ID), note.Review.Ease, strings.TrimRight(fit(brain.FirstLine(note.Content), 60), " "))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.Flags().Bool("stats", false, "Show review statistics instead of reviewing")
+	reviewCmd.Flags().IntP("limit", "l", 20, "Most notes to review in this session (0 for all due)")
+	reviewCmd.Flags().Int("new", 5, "Most never-reviewed notes to bring in")
+	reviewCmd.Flags().StringSliceP("tags", "t", []string{}, "Only review notes with any of these tags")
+	reviewCmd.Flags().Bool("questions", false, "Ask a question generated by the chat model before showing each note")
+}
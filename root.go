@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
 )
 
 var rootCmd = &cobra.Command{
@@ -14,10 +16,39 @@ var rootCmd = &cobra.Command{
 then surface them when you need them using semantic search and context awareness.
 
 Save anything worth remembering, and let your brain remind you when it's relevant.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		brain.ConfigPath, _ = cmd.Flags().GetString("config")
+		brain.CommandName = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	},
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	return explainError(rootCmd.Execute())
+}
+
+// explainError adds a hint for API failures the user can act on, worded
+// for the API that failed
+func explainError(err error) error {
+	var apiErr *brain.APIError
+	chat := errors.As(err, &apiErr) && apiErr.Service == "chat"
+
+	switch {
+	case errors.Is(err, brain.ErrAuth) && chat:
+		return fmt.Errorf("%w\nCheck the API key in the variable named by llm.api_key_env in ~/.brain/config.yaml (OPENAI_API_KEY by default)", err)
+	case errors.Is(err, brain.ErrAuth):
+		return fmt.Errorf("%w\nCheck that OPENAI_API_KEY is set to a valid key, or unset it to use the local embedder", err)
+	case errors.Is(err, brain.ErrQuotaExceeded) && chat:
+		return fmt.Errorf("%w\nYour chat provider account is out of credits; check your plan and billing details", err)
+	case errors.Is(err, brain.ErrQuotaExceeded):
+		return fmt.Errorf("%w\nYour OpenAI account is out of credits; check your plan and billing details", err)
+	case errors.Is(err, brain.ErrBudgetExceeded):
+		return fmt.Errorf("%w\nRaise usage.monthly_budget in ~/.brain/config.yaml, or wait for next month; brain usage shows where it went", err)
+	case errors.Is(err, brain.ErrRateLimited) && chat:
+		return fmt.Errorf("%w\nThe chat API is rate limiting requests; wait a moment and try again", err)
+	case errors.Is(err, brain.ErrRateLimited):
+		return fmt.Errorf("%w\nOpenAI is rate limiting requests; wait a moment and try again", err)
+	}
+	return err
 }
 
 func init() {
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Manage saved searches",
+	Long: `Saved searches keep a query and its filters under a name. Save one
+with brain search --save, re-run it with brain saved run, or use it as a
+collection with --saved on list and export.
+
+Examples:
+  brain search "incident learnings project:payments" --save payments-incidents
+  brain saved list
+  brain saved run payments-incidents
+  brain list --saved payments-incidents
+  brain saved delete payments-incidents`,
+}
+
+var savedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		searches, err := b.SavedSearches()
+		if err != nil {
+			return fmt.Errorf("failed to read saved searches: %w", err)
+		}
+
+		if len(searches) == 0 {
+			fmt.Println("No saved searches.")
+			fmt.Println("Save one with: brain search \"your query\" --save name")
+			return nil
+		}
+
+		for _, s := range searches {
+			fmt.Printf("%s\n   Query: %s\n", s.Name, s.Query)
+			var details []string
+			if len(s.Tags) > 0 {
+				details = append(details, "tags "+strings.Join(s.Tags, ","))
+			}
+			if len(s.AllTags) > 0 {
+				details = append(details, "all tags "+strings.Join(s.AllTags, ","))
+			}
+			if len(s.ExcludeTags) > 0 {
+				details = append(details, "excluding "+strings.Join(s.ExcludeTags, ","))
+			}
+			if s.Mode != "" {
+				details = append(details, string(s.Mode)+" mode")
+			}
+			if len(details) > 0 {
+				fmt.Printf("   Filters: %s\n", strings.Join(details, ", "))
+			}
+			fmt.Printf("   Saved: %s\n\n", s.Created.Format("2006-01-02"))
+		}
+
+		return nil
+	},
+}
+
+var savedRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "Run a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		explain, _ := cmd.Flags().GetBool("explain")
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		search, err := b.SavedSearch(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🔍 %s: %s\n\n", search.Name, search.Query)
+		return runSearch(cmd, b, search.Query, search.Options(limit), explain)
+	},
+}
+
+var savedDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		if err := b.DeleteSavedSearch(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Deleted saved search %q\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(savedCmd)
+	savedCmd.AddCommand(savedListCmd, savedRunCmd, savedDeleteCmd)
+
+	savedRunCmd.Flags().IntP("limit", "l", 5, "Maximum number of results to return (per page)")
+	savedRunCmd.Flags().Bool("explain", false, "Show how each relevance score was computed")
+	addPagingFlags(savedRunCmd)
+}
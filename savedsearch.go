@@ -0,0 +1,158 @@
+package brain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// ErrSavedSearchNotFound is returned when no saved search has the name
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// savedSearchName restricts names to something easy to type
+var savedSearchName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// SavedSearch is a named query with its filters, kept in searches.json.
+// Besides being re-run, it works as a virtual collection of the notes it
+// matches.
+type SavedSearch struct {
+	Name        string     `json:"name"`
+	Query       string     `json:"query"`
+	Tags        []string   `json:"tags,omitempty"`
+	AllTags     []string   `json:"all_tags,omitempty"`
+	ExcludeTags []string   `json:"exclude_tags,omitempty"`
+	Mode        SearchMode `json:"mode,omitempty"`
+	MinScore    float64    `json:"min_score,omitempty"`
+	Diversity   float64    `json:"diversity,omitempty"`
+	Created     time.Time  `json:"created"`
+}
+
+// Options returns the search options the search was saved with
+func (s *SavedSearch) Options(limit int) SearchOptions {
+	return SearchOptions{
+		Limit:       limit,
+		Tags:        s.Tags,
+		AllTags:     s.AllTags,
+		ExcludeTags: s.ExcludeTags,
+		Mode:        s.Mode,
+		MinScore:    s.MinScore,
+		Diversity:   s.Diversity,
+	}
+}
+
+func (b *Brain) savedSearchesPath() string {
+	return filepath.Join(b.dataDir, "searches.json")
+}
+
+// SavedSearches returns all saved searches sorted by name
+func (b *Brain) SavedSearches() ([]*SavedSearch, error) {
+	data, err := os.ReadFile(b.savedSearchesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var searches []*SavedSearch
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", b.savedSearchesPath(), err)
+	}
+	sort.Slice(searches, func(i, j int) bool {
+		return searches[i].Name < searches[j].Name
+	})
+	return searches, nil
+}
+
+// SavedSearch returns the saved search with the given name
+func (b *Brain) SavedSearch(name string) (*SavedSearch, error) {
+	searches, err := b.SavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range searches {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+}
+
+// SaveSearch stores a search, replacing any with the same name. The query
+// is checked first so a typo isn't only found when the search is run.
+func (b *Brain) SaveSearch(search *SavedSearch) error {
+	if !savedSearchName.MatchString(search.Name) {
+		return fmt.Errorf("invalid name %q (use letters, digits, '.', '_' and '-')", search.Name)
+	}
+	if _, err := ParseQuery(search.Query); err != nil {
+		return err
+	}
+
+	searches, err := b.SavedSearches()
+	if err != nil {
+		return err
+	}
+
+	if search.Created.IsZero() {
+		search.Created = time.Now()
+	}
+	kept := searches[:0]
+	for _, s := range searches {
+		if s.Name != search.Name {
+			kept = append(kept, s)
+		}
+	}
+	return b.writeSavedSearches(append(kept, search))
+}
+
+// DeleteSavedSearch removes a saved search
+func (b *Brain) DeleteSavedSearch(name string) error {
+	searches, err := b.SavedSearches()
+	if err != nil {
+		return err
+	}
+
+	kept := searches[:0]
+	for _, s := range searches {
+		if s.Name != name {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(searches) {
+		return fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+	}
+	return b.writeSavedSearches(kept)
+}
+
+func (b *Brain) writeSavedSearches(searches []*SavedSearch) error {
+	data, err := json.MarshalIndent(searches, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.savedSearchesPath(), data, 0644)
+}
+
+// Collection returns every note the saved search matches, best match
+// first
+func (b *Brain) Collection(name string) ([]*Note, error) {
+	search, err := b.SavedSearch(name)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := b.SearchWithOptions(search.Query, search.Options(0))
+	if err != nil {
+		return nil, fmt.Errorf("saved search %s: %w", name, err)
+	}
+
+	notes := make([]*Note, len(results))
+	for i, r := range results {
+		notes[i] = r.Note
+	}
+	return notes, nil
+}
@@ -0,0 +1,84 @@
+package brain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSavedSearches(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, note := range []*Note{
+		{Content: "Incident: double charge after a retry storm", Project: "payments", Tags: []string{"incident"}},
+		{Content: "Incident: cache stampede on the home page", Project: "web", Tags: []string{"incident"}},
+		{Content: "Idempotency keys for payment retries", Project: "payments", Tags: []string{"design"}},
+	} {
+		note.Timestamp = time.Now()
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	if searches, err := b.SavedSearches(); err != nil || len(searches) != 0 {
+		t.Fatalf("Expected no saved searches yet, got %v, %v", searches, err)
+	}
+
+	search := &SavedSearch{Name: "pay-incidents", Query: "project:payments", Tags: []string{"incident"}}
+	if err := b.SaveSearch(search); err != nil {
+		t.Fatalf("SaveSearch failed: %v", err)
+	}
+
+	notes, err := b.Collection("pay-incidents")
+	if err != nil {
+		t.Fatalf("Collection failed: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Project != "payments" || notes[0].Tags[0] != "incident" {
+		t.Errorf("Expected the payments incident only, got %d notes", len(notes))
+	}
+
+	listed, total, err := b.ListNotesWithOptions(ListOptions{Saved: "pay-incidents"})
+	if err != nil || total != 1 || len(listed) != 1 {
+		t.Errorf("Expected list --saved to show 1 note, got %d (%v)", total, err)
+	}
+
+	// Saving under the same name replaces the search
+	search = &SavedSearch{Name: "pay-incidents", Query: "project:payments"}
+	if err := b.SaveSearch(search); err != nil {
+		t.Fatalf("SaveSearch failed: %v", err)
+	}
+	if searches, _ := b.SavedSearches(); len(searches) != 1 {
+		t.Errorf("Expected 1 saved search after replacing, got %d", len(searches))
+	}
+	if notes, _ := b.Collection("pay-incidents"); len(notes) != 2 {
+		t.Errorf("Expected the replaced search to match 2 notes, got %d", len(notes))
+	}
+
+	if err := b.DeleteSavedSearch("pay-incidents"); err != nil {
+		t.Fatalf("DeleteSavedSearch failed: %v", err)
+	}
+	if _, err := b.SavedSearch("pay-incidents"); !errors.Is(err, ErrSavedSearchNotFound) {
+		t.Errorf("Expected ErrSavedSearchNotFound after delete, got %v", err)
+	}
+	if err := b.DeleteSavedSearch("pay-incidents"); !errors.Is(err, ErrSavedSearchNotFound) {
+		t.Errorf("Expected ErrSavedSearchNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestSaveSearchValidation(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"has space", "retries"},
+		{"", "retries"},
+		{"bad-query", "after:yesterday"},
+	}
+
+	for _, tt := range tests {
+		if err := b.SaveSearch(&SavedSearch{Name: tt.name, Query: tt.query}); err == nil {
+			t.Errorf("SaveSearch(%q, %q) should fail", tt.name, tt.query)
+		}
+	}
+}
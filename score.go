@@ -0,0 +1,51 @@
+package brain
+
+// ScoreBreakdown explains how a result's Similarity was computed
+type ScoreBreakdown struct {
+	Mode         SearchMode // how the query text was ranked, empty for filter-only queries
+	Cosine       float64    // raw embedding similarity
+	Keyword      float64    // BM25 score scaled so the best match is 1
+	ProjectBoost float64    // added for notes from the current project
+	RecencyBoost float64    // added for recently written notes
+	UsageBoost   float64    // added for notes that are shown often
+	PinnedBoost  float64    // added for pinned notes
+
+	// Redundancy is the similarity to the closest higher-placed result
+	// when results were diversified; it lowers the placing, not the score
+	Redundancy float64
+}
+
+// Weights returns how much the cosine and keyword scores contribute to
+// the final score in this mode
+func (s ScoreBreakdown) Weights() (cosine, keyword float64) {
+	switch s.Mode {
+	case SearchModeSemantic:
+		return 1, 0
+	case SearchModeKeyword:
+		return 0, 1
+	case SearchModeHybrid:
+		return hybridSemanticWeight, 1 - hybridSemanticWeight
+	}
+	return 0, 0
+}
+
+// Relevance is the score against the query before any boosts
+func (s ScoreBreakdown) Relevance() float64 {
+	cosineWeight, keywordWeight := s.Weights()
+	return cosineWeight*s.Cosine + keywordWeight*s.Keyword
+}
+
+// dropBelow removes results scoring under minScore, keeping the order
+func dropBelow(results []SearchResult, minScore float64) []SearchResult {
+	if minScore <= 0 {
+		return results
+	}
+
+	kept := results[:0]
+	for _, r := range results {
+		if r.Similarity >= minScore {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
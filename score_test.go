@@ -0,0 +1,79 @@
+package brain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSearchMinScoreAndBreakdown(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, content := range []string{
+		"Postgres connection pool sizing for the billing service",
+		"Borrow checker errors when sharing state between threads",
+	} {
+		if err := b.AddNote(&Note{Content: content, Project: "billing", Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	all, err := b.SearchWithOptions("connection pool", SearchOptions{Limit: 5, Mode: SearchModeHybrid})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Expected both notes without a minimum score, got %d", len(all))
+	}
+
+	for _, r := range all {
+		s := r.Score
+		want := math.Min(1, s.Relevance()+s.RecencyBoost+s.UsageBoost+s.PinnedBoost)
+		if math.Abs(want-r.Similarity) > 1e-9 {
+			t.Errorf("Breakdown %+v adds up to %v, want %v", r.Score, want, r.Similarity)
+		}
+	}
+
+	minScore := (all[0].Similarity + all[1].Similarity) / 2
+	kept, err := b.SearchWithOptions("connection pool", SearchOptions{Limit: 5, Mode: SearchModeHybrid, MinScore: minScore})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(kept) != 1 || kept[0].Note != all[0].Note {
+		t.Errorf("Expected only the best note above %v, got %+v", minScore, kept)
+	}
+
+	// Filter-only queries have nothing to score, so the minimum doesn't apply
+	filtered, _ := b.SearchWithOptions("project:billing", SearchOptions{Limit: 5, MinScore: 0.9})
+	if len(filtered) != 2 {
+		t.Errorf("Expected filter-only query to ignore the minimum score, got %d results", len(filtered))
+	}
+}
+
+func TestContextualNotesProjectBoost(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, note := range []*Note{
+		{Content: "Deploy checklist for the api service", Project: "api"},
+		{Content: "Deploy checklist for the web service", Project: "web"},
+	} {
+		note.Timestamp = time.Now()
+		if err := b.AddNote(note); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	results, err := b.GetContextualNotesWithOptions(Context{Project: "web", Keywords: []string{"deploy checklist"}}, SearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("GetContextualNotesWithOptions failed: %v", err)
+	}
+	if len(results) == 0 || results[0].Note.Project != "web" {
+		t.Fatalf("Expected the web note first, got %+v", results)
+	}
+	if results[0].Score.ProjectBoost <= 0 {
+		t.Errorf("Expected a project boost in the breakdown, got %+v", results[0].Score)
+	}
+	for _, r := range results[1:] {
+		if r.Score.ProjectBoost != 0 {
+			t.Errorf("Unexpected project boost for %q", r.Note.Content)
+		}
+	}
+}
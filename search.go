@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/brain-cli/internal/brain"
@@ -12,49 +13,252 @@ var searchCmd = &cobra.Command{
 	Short: "Search your notes semantically",
 	Long: `Search your notes using semantic similarity, not just keywords.
 	
+Queries can mix free text with filters:
+  tag:go            notes tagged go or a child tag like go/concurrency
+                    (repeat to match any of several tags)
+  -tag:draft        skip notes tagged draft
+  project:myapp     notes from a project (-project: to exclude)
+  after:2026-01-01  notes added on or after a date
+  before:2026-06-01 notes added before a date
+  "exact phrase"    notes containing the phrase (-"phrase" to exclude)
+
+Put -- before a query that starts with a negated filter.
+
+Search modes:
+  hybrid    blend of semantic similarity and keyword (BM25) matching (default)
+  semantic  embedding similarity only
+  keyword   exact terms only, best for identifiers and ticket numbers
+
 Examples:
   brain search "making APIs faster"
   brain search "database optimization" --limit 10
-  brain search "performance" --tags go`,
+  brain search "performance" --tags go
+  brain search "retries" --all-tags go,http --exclude-tags draft
+  brain search "ERR_CONN_RESET" --mode keyword
+  brain search 'tag:go after:2026-01-01 -tag:draft "connection pool" timeouts'
+  brain search "retry budget" --min-score 0.3 --explain
+  brain search "caching" --diversity 0.5     # fewer near-duplicate notes
+  brain search "deploy" --limit 20 --page 2
+  brain search "incident learnings project:payments" --save payments-incidents`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		query := args[0]
+		query := joinQueryArgs(args)
 		limit, _ := cmd.Flags().GetInt("limit")
 		tags, _ := cmd.Flags().GetStringSlice("tags")
+		allTags, _ := cmd.Flags().GetStringSlice("all-tags")
+		excludeTags, _ := cmd.Flags().GetStringSlice("exclude-tags")
+		modeFlag, _ := cmd.Flags().GetString("mode")
+		explain, _ := cmd.Flags().GetBool("explain")
+		diversity, _ := cmd.Flags().GetFloat64("diversity")
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+		if diversity < 0 || diversity > 1 {
+			return fmt.Errorf("--diversity must be between 0 and 1")
+		}
+
+		mode, err := brain.ParseSearchMode(modeFlag)
+		if err != nil {
+			return err
+		}
 
 		b, err := brain.New()
 		if err != nil {
 			return fmt.Errorf("failed to initialize brain: %w", err)
 		}
 
-		results, err := b.Search(query, limit, tags)
+		opts := brain.SearchOptions{
+			Limit:       limit,
+			Tags:        tags,
+			AllTags:     allTags,
+			ExcludeTags: excludeTags,
+			Mode:        mode,
+			MinScore:    minScore(cmd, b),
+			Diversity:   diversity,
+		}
+
+		if name, _ := cmd.Flags().GetString("save"); name != "" {
+			err := b.SaveSearch(&brain.SavedSearch{
+				Name:        name,
+				Query:       query,
+				Tags:        tags,
+				AllTags:     allTags,
+				ExcludeTags: excludeTags,
+				Mode:        mode,
+				MinScore:    opts.MinScore,
+				Diversity:   diversity,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save search: %w", err)
+			}
+			fmt.Printf("✓ Saved search %q, run it with: brain saved run %s\n\n", name, name)
+		}
+
+		return runSearch(cmd, b, query, opts, explain)
+	},
+}
+
+// runSearch prints the results of a search page by page
+func runSearch(cmd *cobra.Command, b *brain.Brain, query string, opts brain.SearchOptions, explain bool) error {
+	var shown []brain.SearchResult
+	err := browse(cmd, opts.Limit, func(offset, limit int) (int, bool, error) {
+		// Ask for one extra result to know whether another page follows
+		page := opts
+		page.Offset, page.Limit = offset, limit+1
+		results, err := b.SearchWithOptions(query, page)
 		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+			return 0, false, fmt.Errorf("search failed: %w", err)
+		}
+
+		more := len(results) > limit
+		if more {
+			results = results[:limit]
 		}
 
-		if len(results) == 0 {
+		switch {
+		case len(results) == 0 && offset == 0:
 			fmt.Println("No matching notes found.")
-			return nil
+		case len(results) == 0:
+			fmt.Println("No more results.")
+		case offset == 0 && !more:
+			fmt.Printf("Found %d relevant note(s):\n\n", len(results))
+		default:
+			fmt.Printf("Results %d-%d:\n\n", offset+1, offset+len(results))
 		}
 
-		fmt.Printf("Found %d relevant note(s):\n\n", len(results))
 		for i, result := range results {
-			fmt.Printf("%d. [%s] %s\n", i+1, result.Note.Timestamp.Format("2006-01-02"), result.Note.Content)
-			if len(result.Note.Tags) > 0 {
-				fmt.Printf("   Tags: %v\n", result.Note.Tags)
-			}
-			if result.Note.Project != "" {
-				fmt.Printf("   Project: %s\n", result.Note.Project)
-			}
-			fmt.Printf("   Relevance: %.2f%%\n\n", result.Similarity*100)
+			printSearchResult(offset+i+1, result, explain)
 		}
+		shown = append(shown, results...)
+		return len(results), more, nil
+	})
 
-		return nil
-	},
+	// Recorded once at the end so the ranking doesn't shift between pages
+	recordShown(b, shown)
+	return err
+}
+
+// printSearchResult prints one numbered search result
+func printSearchResult(n int, result brain.SearchResult, explain bool) {
+	fmt.Printf("%d. [%s] %s\n", n, result.Note.Timestamp.Format("2006-01-02"), result.Note.Content)
+	if result.Passage != "" {
+		fmt.Printf("   Matched passage:\n%s\n", indentPassage(result.Passage))
+	}
+	if len(result.Note.Tags) > 0 {
+		fmt.Printf("   Tags: %v\n", result.Note.Tags)
+	}
+	if result.Note.Project != "" {
+		fmt.Printf("   Project: %s\n", result.Note.Project)
+	}
+	fmt.Printf("   Relevance: %.2f%%\n", result.Similarity*100)
+	if explain {
+		printScoreBreakdown(result)
+	}
+	fmt.Println()
+}
+
+// joinQueryArgs rebuilds a query from shell arguments. A single argument
+// is the whole query; with several, any argument the shell unquoted is
+// quoted again so phrases survive.
+func joinQueryArgs(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t") && !strings.Contains(arg, `"`) {
+			arg = `"` + arg + `"`
+		}
+		parts[i] = arg
+	}
+	return strings.Join(parts, " ")
+}
+
+// minScore returns --min-score when given, the configured default otherwise
+func minScore(cmd *cobra.Command, b *brain.Brain) float64 {
+	if cmd.Flags().Changed("min-score") {
+		score, _ := cmd.Flags().GetFloat64("min-score")
+		return score
+	}
+	return b.Config().Search.MinScore
+}
+
+// printScoreBreakdown shows the parts that make up a result's relevance
+func printScoreBreakdown(result brain.SearchResult) {
+	score := result.Score
+	cosineWeight, keywordWeight := score.Weights()
+
+	fmt.Println("   Score breakdown:")
+	if score.Mode == "" {
+		fmt.Println("     filters only, every match scores 100%")
+		return
+	}
+	if cosineWeight > 0 {
+		fmt.Printf("     cosine similarity  %.4f × %.2f\n", score.Cosine, cosineWeight)
+	}
+	if keywordWeight > 0 {
+		fmt.Printf("     keyword (BM25)     %.4f × %.2f\n", score.Keyword, keywordWeight)
+	}
+	if score.RecencyBoost != 0 {
+		fmt.Printf("     recency boost     %+.4f\n", score.RecencyBoost)
+	}
+	if score.UsageBoost != 0 {
+		fmt.Printf("     usage boost       %+.4f\n", score.UsageBoost)
+	}
+	if score.PinnedBoost != 0 {
+		fmt.Printf("     pinned boost      %+.4f\n", score.PinnedBoost)
+	}
+	if score.ProjectBoost != 0 {
+		fmt.Printf("     project boost     %+.4f\n", score.ProjectBoost)
+	}
+	total := score.Relevance() + score.RecencyBoost + score.UsageBoost + score.PinnedBoost + score.ProjectBoost
+	if total > result.Similarity+1e-9 {
+		fmt.Printf("     = %.4f (capped at 1)\n", result.Similarity)
+	} else {
+		fmt.Printf("     = %.4f\n", result.Similarity)
+	}
+	if score.Redundancy != 0 {
+		fmt.Printf("     overlap with results above %.4f (--diversity)\n", score.Redundancy)
+	}
+}
+
+// recordShown counts the results as seen, which ranks often-used notes
+// higher next time. Failing to save that isn't worth failing the command.
+func recordShown(b *brain.Brain, results []brain.SearchResult) {
+	notes := make([]*brain.Note, len(results))
+	for i, result := range results {
+		notes[i] = result.Note
+	}
+	if err := b.RecordAccess(notes...); err != nil {
+		fmt.Printf("⚠ Could not record note usage: %v\n", err)
+	}
+}
+
+// addScoreFlags registers --min-score and --explain
+func addScoreFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("min-score", 0, "Drop results scoring below this (0-1, default from config)")
+	cmd.Flags().Bool("explain", false, "Show how each relevance score was computed")
+}
+
+// indentPassage formats a matched passage of a long note for display
+func indentPassage(passage string) string {
+	lines := strings.Split(strings.TrimSpace(passage), "\n")
+	for i, line := range lines {
+		lines[i] = "   │ " + line
+	}
+	return strings.Join(lines, "\n")
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
-	searchCmd.Flags().IntP("limit", "l", 5, "Maximum number of results to return")
-	searchCmd.Flags().StringSliceP("tags", "t", []string{}, "Filter by tags")
+	searchCmd.Flags().IntP("limit", "l", 5, "Maximum number of results to return (per page)")
+	searchCmd.Flags().StringSliceP("tags", "t", []string{}, "Filter by tags (notes with any of them)")
+	searchCmd.Flags().StringSlice("all-tags", []string{}, "Only notes with all of these tags")
+	searchCmd.Flags().StringSlice("exclude-tags", []string{}, "Skip notes with any of these tags")
+	searchCmd.Flags().StringP("mode", "m", string(brain.SearchModeHybrid), "Ranking mode: hybrid, semantic or keyword")
+	addScoreFlags(searchCmd)
+	addPagingFlags(searchCmd)
+	searchCmd.Flags().String("save", "", "Save the query and filters under this name (see brain saved)")
+	searchCmd.Flags().Float64("diversity", 0, "Favour distinct results over near-duplicates, 0 (off) to 1 (MMR λ = 1 - diversity)")
 }
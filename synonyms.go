@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var synonymsCmd = &cobra.Command{
+	Use:   "synonyms",
+	Short: "Manage the synonym dictionary used to expand queries",
+	Long: `Synonyms and abbreviations are added to search, ask and context
+queries before they are embedded and keyword matched, so a search for
+"k8s" also finds notes about kubernetes. Entries work both ways.
+
+The dictionary lives in ~/.brain/synonyms.yaml and can be edited by hand.
+
+Examples:
+  brain synonyms add k8s kubernetes
+  brain synonyms add pg postgres postgresql
+  brain synonyms add latency "slow response"
+  brain synonyms list
+  brain synonyms remove pg postgresql
+  brain synonyms remove k8s`,
+}
+
+var synonymsAddCmd = &cobra.Command{
+	Use:   "add [term] [synonym...]",
+	Short: "Add synonyms for a term",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		if err := b.AddSynonyms(args[0], args[1:]...); err != nil {
+			return fmt.Errorf("failed to save synonyms: %w", err)
+		}
+
+		fmt.Printf("✓ %s → %s\n", args[0], strings.Join(args[1:], ", "))
+		return nil
+	},
+}
+
+var synonymsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the synonym dictionary",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		synonyms := b.Synonyms()
+		if len(synonyms) == 0 {
+			fmt.Println("No synonyms yet.")
+			fmt.Println("Add one with: brain synonyms add k8s kubernetes")
+			return nil
+		}
+
+		terms := make([]string, 0, len(synonyms))
+		for term := range synonyms {
+			terms = append(terms, term)
+		}
+		sort.Strings(terms)
+
+		for _, term := range terms {
+			fmt.Printf("%s → %s\n", term, strings.Join(synonyms[term], ", "))
+		}
+		return nil
+	},
+}
+
+var synonymsRemoveCmd = &cobra.Command{
+	Use:   "remove [term] [synonym...]",
+	Short: "Remove synonyms, or a term and all its synonyms",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		if err := b.RemoveSynonyms(args[0], args[1:]...); err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			fmt.Printf("✓ Removed %s\n", args[0])
+		} else {
+			fmt.Printf("✓ Removed %s from %s\n", strings.Join(args[1:], ", "), args[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(synonymsCmd)
+	synonymsCmd.AddCommand(synonymsAddCmd, synonymsListCmd, synonymsRemoveCmd)
+}
@@ -0,0 +1,101 @@
+package brain
+
+import (
+	"sort"
+	"strings"
+)
+
+// tagSeparator splits hierarchical tags such as lang/go/concurrency
+const tagSeparator = "/"
+
+// TagFilter selects notes by tag. Filtering by a tag also matches its
+// descendants, so lang/go matches lang/go/concurrency.
+type TagFilter struct {
+	Any     []string // note has at least one of these tags
+	All     []string // note has every one of these tags
+	Exclude []string // note has none of these tags
+}
+
+// Matches reports whether a note with the given tags passes the filter
+func (f TagFilter) Matches(noteTags []string) bool {
+	if len(f.Any) > 0 && !hasAnyTag(noteTags, f.Any) {
+		return false
+	}
+	if len(f.All) > 0 && !hasAllTags(noteTags, f.All) {
+		return false
+	}
+	if len(f.Exclude) > 0 && hasAnyTag(noteTags, f.Exclude) {
+		return false
+	}
+	return true
+}
+
+// hasTag reports whether any of the note's tags is the filter tag or one
+// of its descendants
+func hasTag(noteTags []string, filterTag string) bool {
+	filterTag = strings.Trim(filterTag, tagSeparator)
+	for _, tag := range noteTags {
+		if tag == filterTag || strings.HasPrefix(tag, filterTag+tagSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAllTags(noteTags, filterTags []string) bool {
+	for _, tag := range filterTags {
+		if !hasTag(noteTags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// TagNode is one level of the tag hierarchy
+type TagNode struct {
+	Name     string // last path segment, e.g. "go"
+	Path     string // full tag, e.g. "lang/go"
+	Count    int    // notes tagged with this tag or a descendant
+	Children []*TagNode
+}
+
+// TagTree returns the hierarchy of all tags in use, sorted by name.
+// A note counts once towards every ancestor of each of its tags.
+func (b *Brain) TagTree() []*TagNode {
+	root := &TagNode{}
+	nodes := make(map[string]*TagNode)
+
+	for _, note := range b.vectorStore.GetAllNotes() {
+		counted := make(map[string]bool)
+		for _, tag := range note.Tags {
+			parent := root
+			segments := strings.Split(strings.Trim(tag, tagSeparator), tagSeparator)
+			for i, segment := range segments {
+				path := strings.Join(segments[:i+1], tagSeparator)
+				node, ok := nodes[path]
+				if !ok {
+					node = &TagNode{Name: segment, Path: path}
+					nodes[path] = node
+					parent.Children = append(parent.Children, node)
+				}
+				if !counted[path] {
+					counted[path] = true
+					node.Count++
+				}
+				parent = node
+			}
+		}
+	}
+
+	sortTagNodes(root.Children)
+	return root.Children
+}
+
+func sortTagNodes(nodes []*TagNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortTagNodes(node.Children)
+	}
+}
@@ -0,0 +1,73 @@
+package brain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagFilterMatches(t *testing.T) {
+	noteTags := []string{"lang/go/concurrency", "http", "draft"}
+
+	tests := []struct {
+		name     string
+		filter   TagFilter
+		expected bool
+	}{
+		{"empty filter", TagFilter{}, true},
+		{"any matches one", TagFilter{Any: []string{"rust", "http"}}, true},
+		{"any matches none", TagFilter{Any: []string{"rust"}}, false},
+		{"all present", TagFilter{All: []string{"lang/go", "http"}}, true},
+		{"all missing one", TagFilter{All: []string{"lang/go", "grpc"}}, false},
+		{"exclude present", TagFilter{Exclude: []string{"draft"}}, false},
+		{"exclude parent of tag", TagFilter{Exclude: []string{"lang"}}, false},
+		{"exclude absent", TagFilter{Exclude: []string{"wip"}}, true},
+		{
+			"combined",
+			TagFilter{Any: []string{"lang"}, All: []string{"http"}, Exclude: []string{"wip"}},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(noteTags); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagTree(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	for _, tags := range [][]string{
+		{"lang/go/concurrency", "lang/go"},
+		{"lang/go/testing"},
+		{"lang/rust", "http"},
+	} {
+		if err := b.AddNote(&Note{Content: "note", Tags: tags, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AddNote failed: %v", err)
+		}
+	}
+
+	tree := b.TagTree()
+	if len(tree) != 2 || tree[0].Name != "http" || tree[1].Name != "lang" {
+		t.Fatalf("Expected top-level tags [http lang], got %v", tree)
+	}
+
+	lang := tree[1]
+	if lang.Count != 3 {
+		t.Errorf("Expected lang to count 3 notes, got %d", lang.Count)
+	}
+	if len(lang.Children) != 2 || lang.Children[0].Path != "lang/go" {
+		t.Fatalf("Expected lang children [go rust], got %v", lang.Children)
+	}
+
+	// A note tagged both lang/go and lang/go/concurrency counts once
+	golang := lang.Children[0]
+	if golang.Count != 2 {
+		t.Errorf("Expected lang/go to count 2 notes, got %d", golang.Count)
+	}
+	if len(golang.Children) != 2 || golang.Children[0].Name != "concurrency" || golang.Children[0].Count != 1 {
+		t.Errorf("Unexpected lang/go children: %v", golang.Children)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Show all tags as a tree",
+	Long: `Show every tag in use with the number of notes under it.
+
+Tags containing '/' form a hierarchy: lang/go/concurrency is shown under
+lang/go, and a parent's count includes notes tagged with any of its
+children. Filtering by a parent tag (e.g. --tags lang/go) matches them too.
+
+Examples:
+  brain tags`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := brain.New()
+		if err != nil {
+			return fmt.Errorf("failed to initialize brain: %w", err)
+		}
+
+		tree := b.TagTree()
+		if len(tree) == 0 {
+			fmt.Println("No tags yet.")
+			fmt.Println("Tag a note with: brain add \"your insight\" --tags go,performance")
+			return nil
+		}
+
+		printTagTree(tree)
+		return nil
+	},
+}
+
+// printTagTree prints top-level tags flush left and their children as
+// box-drawing branches
+func printTagTree(nodes []*brain.TagNode) {
+	for _, node := range nodes {
+		fmt.Printf("%s (%d)\n", node.Name, node.Count)
+		printTagBranches(node.Children, "")
+	}
+}
+
+func printTagBranches(nodes []*brain.TagNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s (%d)\n", prefix, branch, node.Name, node.Count)
+		printTagBranches(node.Children, prefix+indent)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,360 @@
+package brain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ErrPromptNotFound is returned for a prompt name that isn't one of
+// PromptNames
+var ErrPromptNotFound = errors.New("unknown prompt")
+
+// promptExt is the extension of prompt template files
+const promptExt = ".tmpl"
+
+// Each prompt template defines a "system" and a "user" template, which
+// become the system and user messages sent to the model
+var defaultPrompts = map[string]string{
+	"ask": `{{define "system"}}{{if .grounded}}` + groundedSystemPrompt + `{{else}}` + askSystemPrompt + `{{end}}{{end}}
+
+{{define "user"}}Notes:
+
+{{if .notes}}{{.notes}}{{else}}(no notes matched this question){{end}}
+
+Question: {{.question}}{{end}}
+`,
+
+	"chat": `{{define "system"}}{{if .grounded}}` + groundedSystemPrompt + `{{else}}` + askSystemPrompt + `{{end}}` + chatHistoryPrompt + `{{end}}
+
+{{define "user"}}Notes:
+
+{{if .notes}}{{.notes}}{{else}}(no notes matched this question){{end}}
+
+Question: {{.question}}{{end}}
+`,
+
+	"digest": `{{define "system"}}` + digestSystemPrompt + `{{end}}
+
+{{define "user"}}Notes from {{.period}}{{if .project}} in project {{.project}}{{end}}:
+
+{{.notes}}{{end}}
+`,
+}
+
+// promptDescriptions say what each prompt is for, in brain prompts list
+var promptDescriptions = map[string]string{
+	"ask":    "Answering a question with brain ask",
+	"chat":   "Each turn of brain chat; earlier turns go between the two messages",
+	"digest": "The overview paragraph of brain digest",
+}
+
+// PromptVariables documents the variables available in every prompt
+// template, e.g. {{.question}}. Variables that don't apply are empty.
+var PromptVariables = [][2]string{
+	{"question", "the question asked (ask and chat)"},
+	{"notes", "the notes found for it, numbered with their IDs, dates, tags and projects"},
+	{"project", "the project: the git repository the command runs in, or digest's --project"},
+	{"context", "where the command runs: its directory and recent commit messages"},
+	{"grounded", "true in grounded mode (ask and chat)"},
+	{"period", "the dates a digest covers, e.g. 2026-10-11 to 2026-10-18"},
+}
+
+// promptData holds the values of the PromptVariables
+type promptData struct {
+	Question string
+	Notes    string
+	Project  string
+	Context  string
+	Grounded bool
+	Period   string
+}
+
+func (d promptData) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"question": d.Question,
+		"notes":    d.Notes,
+		"project":  d.Project,
+		"context":  d.Context,
+		"grounded": d.Grounded,
+		"period":   d.Period,
+	}
+}
+
+// describeContext renders a working context for the context variable
+func describeContext(c *Context) string {
+	if c == nil {
+		return ""
+	}
+	var lines []string
+	if c.Directory != "" {
+		lines = append(lines, "Directory: "+c.Directory)
+	}
+	if c.Project != "" {
+		lines = append(lines, "Project: "+c.Project)
+	}
+	if len(c.Keywords) > 0 {
+		lines = append(lines, "Recent work: "+strings.Join(c.Keywords, "; "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// promptSet holds the prompt templates overridden by files in the
+// prompts directory. A nil *promptSet uses the defaults.
+type promptSet struct {
+	dir       string
+	templates map[string]*template.Template
+	errs      map[string]error // why an override can't be used, by prompt
+}
+
+func promptsPath(dataDir string) string {
+	return filepath.Join(dataDir, "prompts")
+}
+
+// PromptNames returns the names of the prompts that can be overridden
+func PromptNames() []string {
+	names := make([]string, 0, len(defaultPrompts))
+	for name := range defaultPrompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadPrompts reads the prompt templates in dir, which may not exist.
+// Each is checked by rendering it with sample values, so mistakes such
+// as misspelt variables show up straight away rather than mid-answer. A
+// broken template is kept as an error for when its prompt is used, so
+// commands that don't use it still work.
+func loadPrompts(dir string) *promptSet {
+	p := &promptSet{
+		dir:       dir,
+		templates: make(map[string]*template.Template),
+		errs:      make(map[string]error),
+	}
+
+	for _, name := range PromptNames() {
+		data, err := os.ReadFile(p.path(name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			p.errs[name] = err
+			continue
+		}
+
+		tmpl, err := parsePrompt(name, string(data))
+		if err != nil {
+			p.errs[name] = err
+			continue
+		}
+		p.templates[name] = tmpl
+	}
+	return p
+}
+
+// path is where the file overriding a prompt goes
+func (p *promptSet) path(name string) string {
+	return filepath.Join(p.dir, name+promptExt)
+}
+
+// parsePrompt parses a prompt template and checks it renders
+func parsePrompt(name, text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("the file is empty")
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range []string{"system", "user"} {
+		if tmpl.Lookup(part) == nil {
+			return nil, fmt.Errorf(`no {{define "%s"}} template`, part)
+		}
+	}
+
+	// Render both modes, as branches only fail when they run
+	for _, grounded := range []bool{false, true} {
+		sample := promptData{
+			Question: "Sample question?",
+			Notes:    "[1] id 00000000 · 2026-01-01\nSample note",
+			Project:  "sample",
+			Context:  "Directory: /tmp/sample",
+			Grounded: grounded,
+			Period:   "2026-01-01 to 2026-01-08",
+		}
+		messages, err := renderPrompt(tmpl, sample)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range messages {
+			if m.Content == "" {
+				return nil, fmt.Errorf("the %s template is empty", m.Role)
+			}
+		}
+	}
+	return tmpl, nil
+}
+
+// defaultTemplates are the built-in prompts, parsed once
+var defaultTemplates = func() map[string]*template.Template {
+	templates := make(map[string]*template.Template)
+	for name, text := range defaultPrompts {
+		tmpl, err := parsePrompt(name, text)
+		if err != nil {
+			panic(fmt.Sprintf("default %s prompt: %v", name, err))
+		}
+		templates[name] = tmpl
+	}
+	return templates
+}()
+
+// render builds the system and user messages of a prompt
+func (p *promptSet) render(name string, data promptData) ([]Message, error) {
+	if p != nil && p.errs[name] != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", p.path(name), p.errs[name])
+	}
+
+	tmpl := defaultTemplates[name]
+	if p != nil && p.templates[name] != nil {
+		tmpl = p.templates[name]
+	}
+	if tmpl == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+
+	messages, err := renderPrompt(tmpl, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the %s prompt: %w", name, err)
+	}
+	return messages, nil
+}
+
+func renderPrompt(tmpl *template.Template, data promptData) ([]Message, error) {
+	var messages []Message
+	for _, part := range []string{"system", "user"} {
+		var sb strings.Builder
+		if err := tmpl.ExecuteTemplate(&sb, part, data.vars()); err != nil {
+			return nil, err
+		}
+		messages = append(messages, Message{Role: part, Content: strings.TrimSpace(sb.String())})
+	}
+	return messages, nil
+}
+
+// PromptInfo describes a prompt for brain prompts list
+type PromptInfo struct {
+	Name        string
+	Description string
+	Path        string // where an override goes
+	Custom      bool   // overridden by the file at Path
+	Err         error  // why the override can't be used, if it can't
+}
+
+// ListPrompts describes each prompt and checks any file overriding it.
+// Like the other prompt functions it doesn't need a working Brain, so a
+// broken template can still be shown and reset.
+func ListPrompts() ([]PromptInfo, error) {
+	dataDir, err := defaultDataDir()
+	if err != nil {
+		return nil, err
+	}
+	return promptInfos(promptsPath(dataDir)), nil
+}
+
+func promptInfos(dir string) []PromptInfo {
+	p := loadPrompts(dir)
+
+	var infos []PromptInfo
+	for _, name := range PromptNames() {
+		info := PromptInfo{
+			Name:        name,
+			Description: promptDescriptions[name],
+			Path:        p.path(name),
+			Err:         p.errs[name],
+		}
+		if _, err := os.Stat(info.Path); err == nil {
+			info.Custom = true
+		}
+		infos = append(infos, info)
+	}
+
+	// Files that don't override anything are most likely misnamed
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), promptExt)
+		if entry.IsDir() || filepath.Ext(entry.Name()) != promptExt || defaultPrompts[name] != "" {
+			continue
+		}
+		infos = append(infos, PromptInfo{
+			Name:   name,
+			Path:   p.path(name),
+			Custom: true,
+			Err:    fmt.Errorf("%w (prompts are %s)", ErrPromptNotFound, strings.Join(PromptNames(), ", ")),
+		})
+	}
+	return infos
+}
+
+// PromptSource returns the template text of a prompt: the file overriding
+// it, or the default
+func PromptSource(name string) (string, PromptInfo, error) {
+	dataDir, err := defaultDataDir()
+	if err != nil {
+		return "", PromptInfo{}, err
+	}
+	return promptSource(promptsPath(dataDir), name)
+}
+
+func promptSource(dir, name string) (string, PromptInfo, error) {
+	if _, ok := defaultPrompts[name]; !ok {
+		return "", PromptInfo{}, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	for _, info := range promptInfos(dir) {
+		if info.Name != name {
+			continue
+		}
+		if !info.Custom {
+			return defaultPrompts[name], info, nil
+		}
+		data, err := os.ReadFile(info.Path)
+		return string(data), info, err
+	}
+	return "", PromptInfo{}, nil
+}
+
+// DefaultPrompt returns the built-in template text of a prompt
+func DefaultPrompt(name string) (string, error) {
+	text, ok := defaultPrompts[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	return text, nil
+}
+
+// ResetPrompt removes the file overriding a prompt, so the default is
+// used again. It reports whether there was one.
+func ResetPrompt(name string) (bool, error) {
+	dataDir, err := defaultDataDir()
+	if err != nil {
+		return false, err
+	}
+	return resetPrompt(promptsPath(dataDir), name)
+}
+
+func resetPrompt(dir, name string) (bool, error) {
+	if _, ok := defaultPrompts[name]; !ok {
+		return false, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+
+	err := os.Remove(filepath.Join(dir, name+promptExt))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,156 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid override",
+			file:    "ask.tmpl",
+			content: `{{define "system"}}Answer like a pirate.{{end}}{{define "user"}}{{.notes}} {{.question}}{{end}}`,
+		},
+		{
+			name:    "syntax error",
+			file:    "ask.tmpl",
+			content: `{{define "system"}}Hi{{end}}{{define "user"}}{{.question}{{end}}`,
+			wantErr: "invalid prompt template",
+		},
+		{
+			name:    "empty file",
+			file:    "ask.tmpl",
+			content: "\n",
+			wantErr: "empty",
+		},
+		{
+			name:    "missing user template",
+			file:    "digest.tmpl",
+			content: `{{define "system"}}Summarise.{{end}}`,
+			wantErr: `no {{define "user"}} template`,
+		},
+		{
+			name:    "misspelt variable",
+			file:    "chat.tmpl",
+			content: `{{define "system"}}Hi{{end}}{{define "user"}}{{.questoin}}{{end}}`,
+			wantErr: `"questoin"`,
+		},
+		{
+			name:    "misspelt variable in grounded mode",
+			file:    "ask.tmpl",
+			content: `{{define "system"}}{{if .grounded}}{{.nots}}{{else}}Hi{{end}}{{end}}{{define "user"}}{{.question}}{{end}}`,
+			wantErr: `"nots"`,
+		},
+		{
+			name:    "unknown prompt",
+			file:    "summary.tmpl",
+			content: `{{define "system"}}Hi{{end}}{{define "user"}}Hi{{end}}`,
+			wantErr: "unknown prompt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			// A broken template only fails its own prompt
+			prompts := loadPrompts(dir)
+			data := promptData{Question: "How big?", Notes: "[1] Pool size matches cores"}
+			name := strings.TrimSuffix(tt.file, promptExt)
+			_, err := prompts.render(name, data)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			for _, other := range PromptNames() {
+				if _, err := prompts.render(other, data); other != name && err != nil {
+					t.Errorf("Expected the %s prompt to still work, got %v", other, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptOverride(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, last := newChatStub(t, "Arr, size it to the cores [1].")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	path := filepath.Join(promptsPath(b.dataDir), "ask.tmpl")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	override := `{{define "system"}}Answer like a pirate.{{end}}
+{{define "user"}}Project: {{.project}}
+{{.context}}
+
+{{.notes}}
+
+{{.question}}{{end}}`
+	if err := os.WriteFile(path, []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b.prompts = loadPrompts(promptsPath(b.dataDir))
+
+	results := []SearchResult{{Note: &Note{ID: "aaaaaaaa-1111", Content: "Pool size matches cores"}, Similarity: 0.8}}
+	opts := AnswerOptions{Context: &Context{Directory: "/src/payments", Project: "payments"}}
+	if _, err := b.Answer(context.Background(), "How big?", results, opts); err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+	if last.Messages[0].Content != "Answer like a pirate." {
+		t.Errorf("Expected the overridden system prompt, got %q", last.Messages[0].Content)
+	}
+	want := "Project: payments\nDirectory: /src/payments\nProject: payments\n\n[1] id aaaaaaaa"
+	if !strings.HasPrefix(last.Messages[1].Content, want) || !strings.HasSuffix(last.Messages[1].Content, "How big?") {
+		t.Errorf("Unexpected user prompt %q", last.Messages[1].Content)
+	}
+
+	dir := promptsPath(b.dataDir)
+	source, info, err := promptSource(dir, "ask")
+	if err != nil || source != override || !info.Custom || info.Err != nil {
+		t.Errorf("Expected the override as the ask prompt, got %q (%v)", source, err)
+	}
+
+	// Resetting removes the file and goes back to the default
+	if removed, err := resetPrompt(dir, "ask"); err != nil || !removed {
+		t.Fatalf("resetPrompt failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the override to be removed")
+	}
+	if source, _, _ := promptSource(dir, "ask"); source != defaultPrompts["ask"] {
+		t.Error("Expected the default ask prompt after a reset")
+	}
+	if removed, _ := resetPrompt(dir, "ask"); removed {
+		t.Error("Expected nothing to reset the second time")
+	}
+	if _, err := resetPrompt(dir, "summary"); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("Expected ErrPromptNotFound, got %v", err)
+	}
+
+	// Broken and misnamed overrides are reported rather than hidden
+	os.WriteFile(path, []byte(`{{define "system"}}Hi{{end}}`), 0644)
+	os.WriteFile(filepath.Join(dir, "summary.tmpl"), []byte(`{{define "system"}}Hi{{end}}`), 0644)
+	infos := promptInfos(dir)
+	for _, info := range infos {
+		if (info.Name == "ask" || info.Name == "summary") != (info.Err != nil) {
+			t.Errorf("Unexpected validation result for %s: %v", info.Name, info.Err)
+		}
+	}
+	if len(infos) != len(PromptNames())+1 {
+		t.Errorf("Expected the misnamed file to be listed, got %d prompts", len(infos))
+	}
+}
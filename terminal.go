@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by full-screen commands
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	cursorHome   = "\x1b[H"
+	clearLine    = "\x1b[K"
+	styleReverse = "\x1b[7m"
+	styleDim     = "\x1b[2m"
+	styleBold    = "\x1b[1m"
+	styleReset   = "\x1b[0m"
+)
+
+// terminal puts the controlling terminal into raw mode for full-screen
+// commands. It drives the terminal with stty, which every Linux and macOS
+// system has, rather than platform-specific ioctls.
+type terminal struct {
+	saved string // stty settings to restore on exit
+	keys  chan key
+
+	// reading is held while the key reader is inside a read, so suspend
+	// can wait for it to let go of stdin
+	reading sync.Mutex
+	closed  bool
+}
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyCtrl         // r holds the letter, e.g. 'e' for Ctrl-E
+	keyEnter
+	keyBackspace
+	keyEsc
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyUnknown
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// openTerminal switches to raw mode and the alternate screen and starts
+// reading keys
+func openTerminal() (*terminal, error) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return nil, errors.New("this command needs an interactive terminal")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("can't control the terminal (stty: %w)", err)
+	}
+
+	t := &terminal{saved: strings.TrimSpace(saved), keys: make(chan key, 64)}
+	if err := t.enterRawMode(); err != nil {
+		return nil, err
+	}
+
+	go t.readKeys()
+	return t, nil
+}
+
+// Close restores the terminal as it was
+func (t *terminal) Close() {
+	t.suspend()
+	t.closed = true
+	t.reading.Unlock()
+}
+
+// suspend hands the terminal back in its normal mode, e.g. to run an
+// editor. Keys aren't read until resume.
+func (t *terminal) suspend() {
+	t.reading.Lock()
+	fmt.Print(cursorShow + altScreenOff)
+	stty(t.saved)
+}
+
+// resume takes the terminal back after suspend
+func (t *terminal) resume() error {
+	defer t.reading.Unlock()
+	return t.enterRawMode()
+}
+
+func (t *terminal) enterRawMode() error {
+	// Reads time out after 100ms so the key reader can be paused
+	if _, err := stty("raw", "-echo", "min", "0", "time", "1"); err != nil {
+		stty(t.saved)
+		return fmt.Errorf("can't switch the terminal to raw mode: %w", err)
+	}
+	fmt.Print(altScreenOn + cursorHide)
+	return nil
+}
+
+// size returns the terminal's rows and columns
+func (t *terminal) size() (rows, cols int) {
+	out, err := stty("size")
+	if err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			rows, _ = strconv.Atoi(fields[0])
+			cols, _ = strconv.Atoi(fields[1])
+		}
+	}
+	if rows <= 0 || cols <= 0 {
+		return 24, 80
+	}
+	return rows, cols
+}
+
+func (t *terminal) readKeys() {
+	buf := make([]byte, 64)
+	for {
+		t.reading.Lock()
+		if t.closed {
+			t.reading.Unlock()
+			close(t.keys)
+			return
+		}
+		n, err := os.Stdin.Read(buf)
+		t.reading.Unlock()
+
+		if err != nil && err != io.EOF {
+			close(t.keys)
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			t.keys <- k
+		}
+	}
+}
+
+// parseKeys decodes the bytes of one read into key presses
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			k, n := parseEscape(b)
+			keys = append(keys, k)
+			b = b[n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case c < 0x20:
+			keys = append(keys, key{code: keyCtrl, r: rune('a' + c - 1)})
+		default:
+			r, n := utf8.DecodeRune(b)
+			keys = append(keys, key{code: keyRune, r: r})
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes an escape sequence at the start of b, returning the
+// key and how many bytes it used. A lone ESC is the Esc key.
+func parseEscape(b []byte) (key, int) {
+	if len(b) < 3 || (b[1] != '[' && b[1] != 'O') {
+		return key{code: keyEsc}, 1
+	}
+
+	switch b[2] {
+	case 'C', 'D':
+		return key{code: keyUnknown}, 3 // left and right aren't used
+	case 'A':
+		return key{code: keyUp}, 3
+	case 'B':
+		return key{code: keyDown}, 3
+	case 'H':
+		return key{code: keyHome}, 3
+	case 'F':
+		return key{code: keyEnd}, 3
+	}
+
+	// Sequences like ESC [ 5 ~
+	end := 2
+	for end < len(b) && b[end] != '~' && end < 8 {
+		end++
+	}
+	if end < len(b) && b[end] == '~' {
+		switch string(b[2:end]) {
+		case "5":
+			return key{code: keyPageUp}, end + 1
+		case "6":
+			return key{code: keyPageDown}, end + 1
+		case "1", "7":
+			return key{code: keyHome}, end + 1
+		case "4", "8":
+			return key{code: keyEnd}, end + 1
+		}
+		return key{code: keyUnknown}, end + 1
+	}
+	return key{code: keyEsc}, 1
+}
+
+// stty runs stty on the controlling terminal and returns its output
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
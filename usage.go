@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/brain-cli/internal/brain"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show the tokens used and estimated cost of API calls",
+	Long: `Every call to a remote embedding or chat API is logged with its model
+and token counts in ~/.brain/usage.jsonl. This sums them per model and per
+command, with the cost estimated from list prices. Local models are
+counted but cost nothing; set prices for other models under usage.prices
+in ~/.brain/config.yaml.
+
+With usage.monthly_budget set, calls to models with a price are refused
+once the month's estimated cost reaches it.
+
+Examples:
+  brain usage
+  brain usage --since 7d
+  brain usage --since 2026-10-01`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		since, err := brain.ParseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+
+		summary, err := brain.Usage(since, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("📊 API usage since %s\n", since.Format("2006-01-02 15:04"))
+		if summary.Total.Calls == 0 {
+			fmt.Println("\nNo API calls logged in this period.")
+		} else {
+			printUsageTotals("By model", summary.Models)
+			printUsageTotals("By command", summary.Commands)
+			fmt.Println()
+			printUsageTotal("Total", summary.Total)
+			if summary.Total.Estimated > 0 {
+				fmt.Printf("\n💡 %d call(s) didn't report token counts, so they were estimated from the text\n", summary.Total.Estimated)
+			}
+		}
+
+		fmt.Println()
+		if summary.Budget > 0 {
+			fmt.Printf("This month: %s of the %s budget (%.0f%%)\n", dollars(summary.Month), dollars(summary.Budget), summary.Month/summary.Budget*100)
+			if summary.Month >= summary.Budget {
+				fmt.Println("⚠ The budget is spent; calls to paid models are refused until next month")
+			}
+		} else {
+			fmt.Printf("This month: %s (no budget set)\n", dollars(summary.Month))
+		}
+		return nil
+	},
+}
+
+func printUsageTotals(title string, totals []brain.UsageTotal) {
+	fmt.Printf("\n%s:\n", title)
+	for _, total := range totals {
+		printUsageTotal(total.Name, total)
+	}
+}
+
+func printUsageTotal(name string, total brain.UsageTotal) {
+	cost := dollars(total.Cost)
+	if total.Unpriced == total.Calls {
+		cost = "no price"
+	}
+	fmt.Printf("  %s %6d calls %10d in %8d out  %s\n",
+		fit(name, 24), total.Calls, total.PromptTokens, total.CompletionTokens, cost)
+}
+
+// dollars formats an amount in US dollars, with more places for the
+// fractions of a cent most calls cost
+func dollars(amount float64) string {
+	if amount != 0 && amount < 1 {
+		return fmt.Sprintf("$%.4f", amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+	usageCmd.Flags().String("since", "30d", "How far back to look: e.g. 7d, 2w, 12h or a date like 2026-10-01")
+}
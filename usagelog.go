@@ -0,0 +1,327 @@
+package brain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrBudgetExceeded is returned instead of calling a paid model once the
+// month's estimated cost reaches usage.monthly_budget
+var ErrBudgetExceeded = errors.New("monthly API budget exceeded")
+
+// CommandName is the command being run, e.g. "ask" or "synonyms add".
+// It's recorded with each API call in the usage log.
+var CommandName string
+
+// UsageConfig sets model prices and an optional spending limit
+type UsageConfig struct {
+	// MonthlyBudget is the most to spend per calendar month, in US dollars
+	// of estimated cost. Once reached, calls to models with a price are
+	// refused until the month ends. 0 means no limit.
+	MonthlyBudget float64 `yaml:"monthly_budget"`
+	// Prices adds to or replaces the built-in prices, by model name
+	Prices map[string]ModelPrice `yaml:"prices"`
+}
+
+// ModelPrice is what a model costs in US dollars per million tokens
+type ModelPrice struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// defaultPrices are OpenAI's list prices. Models without a price, such as
+// local ones, are counted but cost nothing.
+var defaultPrices = map[string]ModelPrice{
+	"gpt-4o":                 {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+	"gpt-4.1":                {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":           {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano":           {Input: 0.10, Output: 0.40},
+	"gpt-3.5-turbo":          {Input: 0.50, Output: 1.50},
+	"text-embedding-3-small": {Input: 0.02},
+	"text-embedding-3-large": {Input: 0.13},
+	"text-embedding-ada-002": {Input: 0.10},
+}
+
+// UsageRecord is one API call in the usage log
+type UsageRecord struct {
+	Time             time.Time `json:"time"`
+	Command          string    `json:"command,omitempty"`
+	Kind             string    `json:"kind"` // chat or embedding
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens,omitempty"`
+	// Estimated is set when the API didn't report usage and the tokens
+	// were estimated from the text
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// usageLog appends API calls to usage.jsonl in the data directory. A nil
+// *usageLog records nothing and allows everything.
+type usageLog struct {
+	path   string
+	config UsageConfig
+	month  *float64 // cost so far this month, read on first use
+}
+
+func usagePath(dataDir string) string {
+	return filepath.Join(dataDir, "usage.jsonl")
+}
+
+// price finds a model's price. API responses name dated snapshots such as
+// gpt-4o-mini-2024-07-18, so the longest price name the model starts with
+// wins.
+func (c UsageConfig) price(model string) (ModelPrice, bool) {
+	if price, ok := c.exactPrice(model); ok {
+		return price, true
+	}
+
+	best := ""
+	for _, prices := range []map[string]ModelPrice{c.Prices, defaultPrices} {
+		for name := range prices {
+			if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+				best = name
+			}
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return c.exactPrice(best)
+}
+
+func (c UsageConfig) exactPrice(model string) (ModelPrice, bool) {
+	if price, ok := c.Prices[model]; ok {
+		return price, true
+	}
+	price, ok := defaultPrices[model]
+	return price, ok
+}
+
+// cost estimates what a call cost in US dollars
+func (c UsageConfig) cost(r UsageRecord) float64 {
+	price, _ := c.price(r.Model)
+	return (float64(r.PromptTokens)*price.Input + float64(r.CompletionTokens)*price.Output) / 1e6
+}
+
+// allow refuses a call to a model with a price once the monthly budget
+// is spent
+func (l *usageLog) allow(model string) error {
+	if l == nil || l.config.MonthlyBudget <= 0 {
+		return nil
+	}
+	if _, ok := l.config.price(model); !ok {
+		return nil
+	}
+
+	if l.month == nil {
+		records, err := readUsage(l.path, startOfMonth(time.Now()))
+		if err != nil {
+			return err
+		}
+		var spent float64
+		for _, r := range records {
+			spent += l.config.cost(r)
+		}
+		l.month = &spent
+	}
+	if *l.month >= l.config.MonthlyBudget {
+		return fmt.Errorf("%w: $%.2f of $%.2f spent this month", ErrBudgetExceeded, *l.month, l.config.MonthlyBudget)
+	}
+	return nil
+}
+
+// record appends a call to the log. Failing to log doesn't fail the call.
+func (l *usageLog) record(r UsageRecord) {
+	if l == nil {
+		return
+	}
+	r.Time = time.Now()
+	r.Command = CommandName
+	if l.month != nil {
+		*l.month += l.config.cost(r)
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(line, '\n'))
+}
+
+type usageLogKey struct{}
+
+// withUsageLog lets the API clients called with ctx check the budget and
+// record what they used
+func withUsageLog(ctx context.Context, l *usageLog) context.Context {
+	return context.WithValue(ctx, usageLogKey{}, l)
+}
+
+func usageLogFrom(ctx context.Context) *usageLog {
+	l, _ := ctx.Value(usageLogKey{}).(*usageLog)
+	return l
+}
+
+// embed runs the embedder with the usage log in reach
+func (b *Brain) embed(text string) ([]float32, error) {
+	if embedder, ok := b.embedder.(ContextEmbedder); ok {
+		return embedder.EmbedContext(withUsageLog(context.Background(), b.usage), text)
+	}
+	return b.embedder.Embed(text)
+}
+
+// readUsage returns the calls logged since the given time, oldest first
+func readUsage(path string, since time.Time) ([]UsageRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []UsageRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r UsageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // a line cut short by a crash
+		}
+		if !r.Time.Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// UsageTotal adds up the calls to one model or from one command
+type UsageTotal struct {
+	Name             string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // estimated, in US dollars
+	Unpriced         int     // calls to models without a price
+	Estimated        int     // calls whose tokens were estimated
+}
+
+func (t *UsageTotal) add(r UsageRecord, cost float64, priced bool) {
+	t.Calls++
+	t.PromptTokens += r.PromptTokens
+	t.CompletionTokens += r.CompletionTokens
+	t.Cost += cost
+	if !priced {
+		t.Unpriced++
+	}
+	if r.Estimated {
+		t.Estimated++
+	}
+}
+
+// UsageSummary totals the usage log over a period
+type UsageSummary struct {
+	Since    time.Time
+	Total    UsageTotal
+	Models   []UsageTotal // most expensive first
+	Commands []UsageTotal // most expensive first
+	// Month is the estimated cost so far this calendar month, which the
+	// Budget applies to
+	Month  float64
+	Budget float64
+}
+
+// Usage summarises the API calls logged since the given time. It only
+// reads the config and the log, so no notes are loaded or embedded.
+func Usage(since, now time.Time) (*UsageSummary, error) {
+	dataDir, err := defaultDataDir()
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(configPath(dataDir))
+	if err != nil {
+		return nil, err
+	}
+	return summarizeUsage(usagePath(dataDir), config.Usage, since, now)
+}
+
+func summarizeUsage(path string, config UsageConfig, since, now time.Time) (*UsageSummary, error) {
+	monthStart := startOfMonth(now)
+	from := since
+	if monthStart.Before(from) {
+		from = monthStart
+	}
+	records, err := readUsage(path, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the usage log: %w", err)
+	}
+
+	summary := &UsageSummary{Since: since, Budget: config.MonthlyBudget}
+	models := make(map[string]*UsageTotal)
+	commands := make(map[string]*UsageTotal)
+	for _, r := range records {
+		cost := config.cost(r)
+		if !r.Time.Before(monthStart) {
+			summary.Month += cost
+		}
+		if r.Time.Before(since) {
+			continue
+		}
+
+		_, priced := config.price(r.Model)
+		command := r.Command
+		if command == "" {
+			command = "(unknown)"
+		}
+		summary.Total.add(r, cost, priced)
+		usageTotal(models, r.Model).add(r, cost, priced)
+		usageTotal(commands, command).add(r, cost, priced)
+	}
+
+	summary.Models = sortedTotals(models)
+	summary.Commands = sortedTotals(commands)
+	return summary, nil
+}
+
+func usageTotal(totals map[string]*UsageTotal, name string) *UsageTotal {
+	if totals[name] == nil {
+		totals[name] = &UsageTotal{Name: name}
+	}
+	return totals[name]
+}
+
+func sortedTotals(totals map[string]*UsageTotal) []UsageTotal {
+	result := make([]UsageTotal, 0, len(totals))
+	for _, t := range totals {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cost != result[j].Cost {
+			return result[i].Cost > result[j].Cost
+		}
+		ti := result[i].PromptTokens + result[i].CompletionTokens
+		tj := result[j].PromptTokens + result[j].CompletionTokens
+		if ti != tj {
+			return ti > tj
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
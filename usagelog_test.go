@@ -0,0 +1,150 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestModelPrice(t *testing.T) {
+	config := UsageConfig{Prices: map[string]ModelPrice{
+		"llama3":      {},
+		"gpt-4o-mini": {Input: 1, Output: 2},
+	}}
+
+	tests := []struct {
+		model     string
+		wantPrice ModelPrice
+		wantOK    bool
+	}{
+		{model: "gpt-4o", wantPrice: ModelPrice{Input: 2.50, Output: 10.00}, wantOK: true},
+		{model: "gpt-4o-2024-08-06", wantPrice: ModelPrice{Input: 2.50, Output: 10.00}, wantOK: true},
+		{model: "gpt-4o-mini-2024-07-18", wantPrice: ModelPrice{Input: 1, Output: 2}, wantOK: true},
+		{model: "text-embedding-3-small", wantPrice: ModelPrice{Input: 0.02}, wantOK: true},
+		{model: "llama3", wantOK: true},
+		{model: "mistral", wantOK: false},
+		{model: "gpt-4omni", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			price, ok := config.price(tt.model)
+			if ok != tt.wantOK || price != tt.wantPrice {
+				t.Errorf("Expected %+v (%v), got %+v (%v)", tt.wantPrice, tt.wantOK, price, ok)
+			}
+		})
+	}
+}
+
+func TestUsageLog(t *testing.T) {
+	b := newTestBrain(t, NewLocalEmbedder())
+	server, _ := newChatStub(t, "Size it to the cores.")
+	b.llm = NewOpenAIChat(server.URL+"/v1", "test-model", "test-key")
+
+	// The stub reports 120 prompt and 30 completion tokens a call, which
+	// cost $0.00018 at these prices, so the third call is over budget
+	b.config.Usage = UsageConfig{
+		MonthlyBudget: 0.0003,
+		Prices:        map[string]ModelPrice{"test-model": {Input: 1, Output: 2}},
+	}
+	b.usage = &usageLog{path: usagePath(b.dataDir), config: b.config.Usage}
+	CommandName = "ask"
+	defer func() { CommandName = "" }()
+
+	messages := []Message{{Role: "user", Content: "How big?"}}
+	for i := 0; i < 2; i++ {
+		if _, err := b.complete(context.Background(), messages, nil); err != nil {
+			t.Fatalf("Call %d failed: %v", i+1, err)
+		}
+	}
+	if _, err := b.complete(context.Background(), messages, nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+	}
+
+	// Embeddings are logged too, estimated when the API doesn't say
+	embeddings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"embedding": [0.6, 0.8]}]}`))
+	}))
+	defer embeddings.Close()
+	b.embedder = &OpenAIEmbedder{apiKey: "test", model: "local-embedder", url: embeddings.URL, client: embeddings.Client()}
+	if _, err := b.embed("twelve chars"); err != nil {
+		t.Fatalf("embed failed: %v", err)
+	}
+
+	now := time.Now()
+	summary, err := summarizeUsage(b.usage.path, b.config.Usage, now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if summary.Total.Calls != 3 || summary.Total.PromptTokens != 243 || summary.Total.CompletionTokens != 60 {
+		t.Errorf("Unexpected total %+v", summary.Total)
+	}
+	if !approxEqual(summary.Month, 0.00036) || summary.Budget != 0.0003 {
+		t.Errorf("Expected $0.00036 of the $0.0003 budget spent, got %v of %v", summary.Month, summary.Budget)
+	}
+
+	if len(summary.Models) != 2 || summary.Models[0].Name != "test-model-0613" || summary.Models[0].Calls != 2 {
+		t.Fatalf("Unexpected models %+v", summary.Models)
+	}
+	if embedding := summary.Models[1]; embedding.Unpriced != 1 || embedding.Estimated != 1 || embedding.PromptTokens != 3 {
+		t.Errorf("Expected an estimated, unpriced embedding call, got %+v", embedding)
+	}
+	if len(summary.Commands) != 1 || summary.Commands[0].Name != "ask" || summary.Commands[0].Calls != 3 {
+		t.Errorf("Unexpected commands %+v", summary.Commands)
+	}
+
+	// Calls to models without a price aren't blocked
+	if err := b.usage.allow("local-embedder"); err != nil {
+		t.Errorf("Expected an unpriced model to be allowed, got %v", err)
+	}
+}
+
+func TestLoadNotesOverBudget(t *testing.T) {
+	requests := 0
+	embeddings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": [{"embedding": [0.6, 0.8]}], "usage": {"prompt_tokens": 1000, "total_tokens": 1000}}`))
+	}))
+	defer embeddings.Close()
+
+	// One call costs $0.001, which spends the whole budget
+	b := newTestBrain(t, &OpenAIEmbedder{apiKey: "test", model: "test-embedding", url: embeddings.URL, client: embeddings.Client()})
+	b.config.Usage = UsageConfig{
+		MonthlyBudget: 0.001,
+		Prices:        map[string]ModelPrice{"test-embedding": {Input: 1}},
+	}
+	b.usage = &usageLog{path: usagePath(b.dataDir), config: b.config.Usage}
+
+	embedded := &Note{Content: "Embedded before the budget ran out", Timestamp: time.Now()}
+	if err := b.AddNote(embedded); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	for _, id := range []string{"synced-1", "synced-2"} {
+		b.vectorStore.Add(&Note{ID: id, Content: "Synced from another machine " + id, Timestamp: time.Now()})
+	}
+	if err := b.saveNotes(); err != nil {
+		t.Fatalf("saveNotes failed: %v", err)
+	}
+
+	// Reloading uses the saved embedding and leaves the rest pending
+	// instead of failing or calling the API
+	requests = 0
+	b.usage = &usageLog{path: usagePath(b.dataDir), config: b.config.Usage}
+	if err := b.loadNotes(); err != nil {
+		t.Fatalf("loadNotes failed: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no embedding requests over budget, got %d", requests)
+	}
+	for _, note := range b.vectorStore.GetAllNotes() {
+		if wantPending := note.ID != embedded.ID; note.Pending != wantPending {
+			t.Errorf("Note %q: expected pending %v", note.Content, wantPending)
+		}
+	}
+	if results, _ := b.vectorStore.Search([]float32{0.6, 0.8}, 3, nil); len(results) != 1 || results[0].Note.ID != embedded.ID {
+		t.Errorf("Expected the embedded note to stay searchable, got %d results", len(results))
+	}
+}
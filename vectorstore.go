@@ -9,19 +9,46 @@ import (
 // VectorStore interface for storing and searching embeddings
 type VectorStore interface {
 	Add(note *Note) error
+	Remove(note *Note) error
 	Search(embedding []float32, limit int, tags []string) ([]SearchResult, error)
 	GetAllNotes() []*Note
 }
 
+// embeddingSource is implemented by stores that can return the embedding
+// they hold for a note, which may only exist in compressed form
+type embeddingSource interface {
+	Embedding(note *Note) []float32
+}
+
+// VectorStoreOptions controls how embeddings are held in memory
+type VectorStoreOptions struct {
+	// Precision of stored embeddings. float16 and int8 use 2x and 4x less
+	// memory than float32 at a small cost in recall.
+	Precision Precision `yaml:"precision"`
+
+	// Rerank re-scores this many top candidates with exact float32
+	// similarity. Full precision embeddings are kept in memory when set.
+	Rerank int `yaml:"rerank"`
+}
+
 // SimpleVectorStore is an in-memory vector store
 type SimpleVectorStore struct {
-	mu    sync.RWMutex
-	notes []*Note
+	mu         sync.RWMutex
+	notes      []*Note
+	opts       VectorStoreOptions
+	compressed []*compressedNote // parallel to notes when quantizing
 }
 
-func NewSimpleVectorStore(dataDir string) (*SimpleVectorStore, error) {
+// compressedNote holds quantized copies of a note's embeddings
+type compressedNote struct {
+	embedding quantizedVector
+	chunks    []quantizedVector
+}
+
+func NewSimpleVectorStore(dataDir string, opts VectorStoreOptions) (*SimpleVectorStore, error) {
 	return &SimpleVectorStore{
 		notes: make([]*Note, 0),
+		opts:  opts,
 	}, nil
 }
 
@@ -30,27 +57,175 @@ func (s *SimpleVectorStore) Add(note *Note) error {
 	defer s.mu.Unlock()
 	
 	s.notes = append(s.notes, note)
+	if s.quantized() {
+		s.compressed = append(s.compressed, s.compress(note))
+	}
 	return nil
 }
 
+func (s *SimpleVectorStore) Remove(note *Note) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, n := range s.notes {
+		if n != note {
+			continue
+		}
+		s.notes = append(s.notes[:i], s.notes[i+1:]...)
+		if s.quantized() {
+			s.compressed = append(s.compressed[:i], s.compressed[i+1:]...)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Embedding returns the stored embedding of a note, decompressed if the
+// float32 original was released. It's nil for pending notes.
+func (s *SimpleVectorStore) Embedding(note *Note) []float32 {
+	if len(note.Embedding) > 0 || !s.quantized() {
+		return note.Embedding
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i, n := range s.notes {
+		if n == note && s.compressed[i] != nil {
+			return s.compressed[i].embedding.decode()
+		}
+	}
+	return nil
+}
+
+func (s *SimpleVectorStore) quantized() bool {
+	return s.opts.Precision == PrecisionFloat16 || s.opts.Precision == PrecisionInt8
+}
+
+// compress quantizes the note's embeddings. Unless re-ranking needs them,
+// the float32 originals are released so only the compressed copy is kept.
+func (s *SimpleVectorStore) compress(note *Note) *compressedNote {
+	if len(note.Embedding) == 0 {
+		return nil // Pending notes are scored exactly once they're embedded
+	}
+
+	c := &compressedNote{embedding: quantize(note.Embedding, s.opts.Precision)}
+	for _, chunk := range note.Chunks {
+		c.chunks = append(c.chunks, quantize(chunk.Embedding, s.opts.Precision))
+	}
+
+	if s.opts.Rerank == 0 {
+		note.Embedding = nil
+		for i := range note.Chunks {
+			note.Chunks[i].Embedding = nil
+		}
+	}
+
+	return c
+}
+
+// restore adds a note with embeddings loaded from disk. Compressed vectors
+// are kept as they are if the store holds that precision, and otherwise
+// decoded and added like freshly made ones.
+func (s *SimpleVectorStore) restore(note *Note, saved savedEmbedding) {
+	var passages []string
+	if len(saved.Chunks) > 0 {
+		passages = splitIntoChunks(note.Content, maxChunkChars, chunkOverlapChars)
+	}
+
+	note.Chunks = nil
+	if !s.quantized() || s.opts.Rerank > 0 || saved.Embedding.precision() != s.opts.Precision {
+		note.Embedding = saved.Embedding.decode()
+		for i, chunk := range saved.Chunks {
+			note.Chunks = append(note.Chunks, Chunk{Text: passages[i], Embedding: chunk.decode()})
+		}
+		s.Add(note)
+		return
+	}
+
+	c := &compressedNote{embedding: saved.Embedding.vector()}
+	for i, chunk := range saved.Chunks {
+		c.chunks = append(c.chunks, chunk.vector())
+		note.Chunks = append(note.Chunks, Chunk{Text: passages[i]})
+	}
+	note.Embedding = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notes = append(s.notes, note)
+	s.compressed = append(s.compressed, c)
+}
+
+// savedEmbeddings returns the embeddings of every embedded note, by ID.
+// Notes whose float32 originals were released are saved compressed.
+func (s *SimpleVectorStore) savedEmbeddings() map[string]savedEmbedding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	saved := make(map[string]savedEmbedding, len(s.notes))
+	for i, note := range s.notes {
+		entry := savedEmbedding{Hash: contentHash(note.Content)}
+		if len(note.Embedding) > 0 {
+			entry.Embedding = savedVector{Float32: note.Embedding}
+			for _, chunk := range note.Chunks {
+				entry.Chunks = append(entry.Chunks, savedVector{Float32: chunk.Embedding})
+			}
+		} else if s.quantized() && s.compressed[i] != nil {
+			entry.Embedding = saveVector(s.compressed[i].embedding)
+			for _, chunk := range s.compressed[i].chunks {
+				entry.Chunks = append(entry.Chunks, saveVector(chunk))
+			}
+		} else {
+			continue // Pending
+		}
+		saved[note.ID] = entry
+	}
+	return saved
+}
+
+// similarity mirrors noteSimilarity on the compressed vectors
+func (c *compressedNote) similarity(query []float32, queryNorm float64, note *Note) (float64, string) {
+	if len(c.chunks) == 0 {
+		return quantizedCosine(query, queryNorm, c.embedding), ""
+	}
+
+	best, passage := -1.0, ""
+	for i, chunk := range c.chunks {
+		if similarity := quantizedCosine(query, queryNorm, chunk); similarity > best {
+			best, passage = similarity, note.Chunks[i].Text
+		}
+	}
+	return best, passage
+}
+
 func (s *SimpleVectorStore) Search(embedding []float32, limit int, tags []string) ([]SearchResult, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	results := make([]SearchResult, 0)
+	queryNorm := vectorNorm(embedding)
 
-	for _, note := range s.notes {
+	for i, note := range s.notes {
 		// Filter by tags if specified
 		if len(tags) > 0 && !hasAnyTag(note.Tags, tags) {
 			continue
 		}
 
-		// Calculate cosine similarity
-		similarity := cosineSimilarity(embedding, note.Embedding)
+		// Calculate cosine similarity, on the compressed copy if we have one
+		var similarity float64
+		var passage string
+		if s.quantized() && s.compressed[i] != nil {
+			similarity, passage = s.compressed[i].similarity(embedding, queryNorm, note)
+		} else if len(note.Embedding) > 0 {
+			similarity, passage = noteSimilarity(embedding, note)
+		} else {
+			continue // Notes still waiting for an embedding can't be ranked
+		}
 		
 		results = append(results, SearchResult{
 			Note:       note,
 			Similarity: similarity,
+			Passage:    passage,
 		})
 	}
 
@@ -59,6 +234,10 @@ func (s *SimpleVectorStore) Search(embedding []float32, limit int, tags []string
 		return results[i].Similarity > results[j].Similarity
 	})
 
+	if s.quantized() && s.opts.Rerank > 0 {
+		rerank(embedding, results, s.opts.Rerank)
+	}
+
 	// Limit results
 	if len(results) > limit {
 		results = results[:limit]
@@ -99,14 +278,42 @@ func cosineSimilarity(a, b []float32) float64 {
 	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
-func hasAnyTag(noteTags, filterTags []string) bool {
-	tagSet := make(map[string]bool)
-	for _, tag := range noteTags {
-		tagSet[tag] = true
+// rerank re-scores the top n results with exact similarity and re-sorts them
+func rerank(embedding []float32, results []SearchResult, n int) {
+	if n > len(results) {
+		n = len(results)
+	}
+
+	for i := range results[:n] {
+		if len(results[i].Note.Embedding) > 0 {
+			results[i].Similarity, results[i].Passage = noteSimilarity(embedding, results[i].Note)
+		}
+	}
+
+	sort.SliceStable(results[:n], func(i, j int) bool {
+		return results[i].Similarity > results[j].Similarity
+	})
+}
+
+// noteSimilarity scores a note against the query. Long notes score as
+// their best matching passage, which is returned alongside.
+func noteSimilarity(embedding []float32, note *Note) (float64, string) {
+	if len(note.Chunks) == 0 {
+		return cosineSimilarity(embedding, note.Embedding), ""
 	}
 
+	best, passage := -1.0, ""
+	for _, chunk := range note.Chunks {
+		if similarity := cosineSimilarity(embedding, chunk.Embedding); similarity > best {
+			best, passage = similarity, chunk.Text
+		}
+	}
+	return best, passage
+}
+
+func hasAnyTag(noteTags, filterTags []string) bool {
 	for _, tag := range filterTags {
-		if tagSet[tag] {
+		if hasTag(noteTags, tag) {
 			return true
 		}
 	}